@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -12,6 +13,8 @@ import (
 	"test-management-service/internal/repository"
 	"test-management-service/internal/service"
 	"test-management-service/internal/testcase"
+	"test-management-service/internal/websocket"
+	"test-management-service/internal/workflow"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/sqlite"
@@ -41,9 +44,17 @@ func main() {
 		&models.TestGroup{},
 		&models.TestCase{},
 		&models.TestResult{},
+		&models.TestArtifact{},
 		&models.TestRun{},
 		&models.Environment{},
 		&models.EnvironmentVariable{},
+		&models.JSONSchema{},
+		&models.Snapshot{},
+		&models.Workflow{},
+		&models.WorkflowRun{},
+		&models.WorkflowStepExecution{},
+		&models.WorkflowStepLog{},
+		&models.WorkflowVariableChange{},
 	); err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
@@ -55,20 +66,49 @@ func main() {
 	runRepo := repository.NewTestRunRepository(db)
 	envRepo := repository.NewEnvironmentRepository(db)
 	envVarRepo := repository.NewEnvironmentVariableRepository(db)
+	schemaRepo := repository.NewJSONSchemaRepository(db)
+	snapshotRepo := repository.NewSnapshotRepository(db)
+	execCaseRepo := repository.NewWorkflowTestCaseRepository(db)
+	workflowRepo := repository.NewWorkflowRepository(db)
+	workflowRunRepo := repository.NewWorkflowRunRepository(db)
+	stepExecRepo := repository.NewStepExecutionRepository(db)
+	stepLogRepo := repository.NewStepLogRepository(db)
 
 	// Initialize environment service and variable injector
 	envService := service.NewEnvironmentService(envRepo, envVarRepo)
 	variableInjector := service.NewVariableInjector(envService)
 
-	// Initialize executor with variable injection
-	executor := testcase.NewExecutorWithInjector(cfg.Test.TargetHost, nil, caseRepo, nil, variableInjector)
+	// Setup WebSocket hub for run notifications
+	hub := websocket.NewHub()
+	go hub.Run()
+
+	// Initialize executor with variable injection; workflow tests and workflow steps call each other,
+	// so the workflow executor is attached to the adapter once both exist
+	workflowAdapter := &workflow.TestCaseAdapter{}
+	executor := testcase.NewExecutorWithInjector(cfg.Test.TargetHost, workflowAdapter, execCaseRepo, workflowRepo, variableInjector)
+	executor.SetSchemaRepository(schemaRepo)
+	executor.SetSnapshotRepository(snapshotRepo)
+	workflowExecutor := workflow.NewWorkflowExecutor(db, execCaseRepo, workflowRepo, executor, hub, variableInjector)
+	workflowAdapter.Executor = workflowExecutor
 
 	// Initialize service
-	testService := service.NewTestService(caseRepo, groupRepo, resultRepo, runRepo, executor)
+	testService := service.NewTestService(caseRepo, groupRepo, resultRepo, runRepo, executor, hub, cfg.Test.Concurrency)
+
+	// Mark runs left unfinished by a previous process, then start executing queued runs
+	if interrupted, err := testService.RecoverInterruptedRuns(); err != nil {
+		log.Printf("Failed to recover interrupted test runs: %v", err)
+	} else if interrupted > 0 {
+		log.Printf("Marked %d unfinished test runs as interrupted", interrupted)
+	}
+	testService.StartRunWorkers(context.Background(), cfg.Test.Workers)
 
 	// Initialize handlers
 	testHandler := handler.NewTestHandler(testService)
+	workflowHandler := handler.NewWorkflowHandler(service.NewWorkflowService(workflowRepo, workflowRunRepo, stepExecRepo, stepLogRepo, execCaseRepo, workflowExecutor))
 	envHandler := handler.NewEnvironmentHandler(envService)
+	schemaHandler := handler.NewSchemaHandler(service.NewSchemaService(schemaRepo))
+	snapshotHandler := handler.NewSnapshotHandler(service.NewSnapshotService(snapshotRepo))
+	wsHandler := handler.NewWebSocketHandler(hub)
 
 	// Setup Gin router
 	r := gin.Default()
@@ -78,7 +118,11 @@ func main() {
 
 	// Register routes
 	testHandler.RegisterRoutes(r)
+	workflowHandler.RegisterRoutes(r)
 	envHandler.RegisterRoutes(r)
+	schemaHandler.RegisterRoutes(r)
+	snapshotHandler.RegisterRoutes(r)
+	wsHandler.RegisterRoutes(r)
 
 	// Serve static files (Web UI)
 	r.Static("/web", "./web")
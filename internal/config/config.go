@@ -30,6 +30,8 @@ type DatabaseConfig struct {
 type TestConfig struct {
 	TargetHost   string `toml:"target_host"`   // 被测试服务的地址
 	RegistryPath string `toml:"registry_path"` // 测试用例注册路径（可选，用于导入）
+	Concurrency  int    `toml:"concurrency"`   // 分组运行默认并发数，默认 1（串行）
+	Workers      int    `toml:"workers"`       // 同时执行的排队运行数，默认 2
 }
 
 // LoadConfig 加载配置文件
@@ -60,6 +62,12 @@ func LoadConfig(path string) (*Config, error) {
 	if config.Database.DSN == "" {
 		config.Database.DSN = "./data/test_management.db"
 	}
+	if config.Test.Concurrency <= 0 {
+		config.Test.Concurrency = 1
+	}
+	if config.Test.Workers <= 0 {
+		config.Test.Workers = 2
+	}
 
 	return &config, nil
 }
@@ -59,7 +59,7 @@ func (h *EnvironmentHandler) CreateEnvironment(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, env)
+	c.JSON(http.StatusCreated, service.MaskSecrets(env))
 }
 
 // ListEnvironments lists all environments with pagination
@@ -74,6 +74,10 @@ func (h *EnvironmentHandler) ListEnvironments(c *gin.Context) {
 		return
 	}
 
+	for i := range environments {
+		environments[i] = *service.MaskSecrets(&environments[i])
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"data":   environments,
 		"total":  total,
@@ -97,7 +101,7 @@ func (h *EnvironmentHandler) GetEnvironment(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, env)
+	c.JSON(http.StatusOK, service.MaskSecrets(env))
 }
 
 // UpdateEnvironment updates an existing environment
@@ -117,7 +121,7 @@ func (h *EnvironmentHandler) UpdateEnvironment(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, env)
+	c.JSON(http.StatusOK, service.MaskSecrets(env))
 }
 
 // DeleteEnvironment deletes an environment
@@ -146,7 +150,7 @@ func (h *EnvironmentHandler) GetActiveEnvironment(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, env)
+	c.JSON(http.StatusOK, service.MaskSecrets(env))
 }
 
 // ActivateEnvironment activates a specific environment
@@ -212,16 +216,17 @@ func (h *EnvironmentHandler) SetVariable(c *gin.Context) {
 		return
 	}
 
-	err := h.envService.SetVariable(envID, key, req.Value)
+	err := h.envService.SetVariable(envID, key, req.Value, req.Secret)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	value, _ := h.envService.GetVariable(envID, key)
 	c.JSON(http.StatusOK, gin.H{
 		"message": "variable updated",
 		"key":     key,
-		"value":   req.Value,
+		"value":   value,
 	})
 }
 
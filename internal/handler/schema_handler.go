@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"test-management-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SchemaHandler handles HTTP requests for reusable JSON schema management
+type SchemaHandler struct {
+	schemaService service.SchemaService
+}
+
+// NewSchemaHandler creates a new schema handler
+func NewSchemaHandler(schemaService service.SchemaService) *SchemaHandler {
+	return &SchemaHandler{
+		schemaService: schemaService,
+	}
+}
+
+// RegisterRoutes registers all schema-related routes
+func (h *SchemaHandler) RegisterRoutes(r *gin.Engine) {
+	api := r.Group("/api/v2/schemas")
+	{
+		api.POST("", h.CreateSchema)
+		api.GET("", h.ListSchemas)
+		api.GET("/:id", h.GetSchema)
+		api.PUT("/:id", h.UpdateSchema)
+		api.DELETE("/:id", h.DeleteSchema)
+	}
+}
+
+// CreateSchema creates a new schema
+// POST /api/v2/schemas
+func (h *SchemaHandler) CreateSchema(c *gin.Context) {
+	var req service.CreateSchemaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schema, err := h.schemaService.CreateSchema(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, schema)
+}
+
+// ListSchemas lists all schemas with pagination
+// GET /api/v2/schemas?limit=20&offset=0
+func (h *SchemaHandler) ListSchemas(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	schemas, total, err := h.schemaService.ListSchemas(limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   schemas,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetSchema retrieves a specific schema by ID
+// GET /api/v2/schemas/:id
+func (h *SchemaHandler) GetSchema(c *gin.Context) {
+	schemaID := c.Param("id")
+
+	schema, err := h.schemaService.GetSchema(schemaID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if schema == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "schema not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, schema)
+}
+
+// UpdateSchema updates an existing schema
+// PUT /api/v2/schemas/:id
+func (h *SchemaHandler) UpdateSchema(c *gin.Context) {
+	schemaID := c.Param("id")
+
+	var req service.UpdateSchemaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schema, err := h.schemaService.UpdateSchema(schemaID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, schema)
+}
+
+// DeleteSchema deletes a schema
+// DELETE /api/v2/schemas/:id
+func (h *SchemaHandler) DeleteSchema(c *gin.Context) {
+	schemaID := c.Param("id")
+
+	if err := h.schemaService.DeleteSchema(schemaID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "schema deleted"})
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"test-management-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SnapshotHandler handles HTTP requests for reviewing and accepting response snapshots
+type SnapshotHandler struct {
+	snapshotService service.SnapshotService
+}
+
+// NewSnapshotHandler creates a new snapshot handler
+func NewSnapshotHandler(snapshotService service.SnapshotService) *SnapshotHandler {
+	return &SnapshotHandler{
+		snapshotService: snapshotService,
+	}
+}
+
+// RegisterRoutes registers all snapshot-related routes
+func (h *SnapshotHandler) RegisterRoutes(r *gin.Engine) {
+	api := r.Group("/api/v2")
+	{
+		api.GET("/snapshots/pending", h.ListPendingSnapshots)
+		api.GET("/tests/:id/snapshots", h.ListSnapshots)
+		api.GET("/tests/:id/snapshots/:name", h.GetSnapshot)
+		api.POST("/tests/:id/snapshots/:name/accept", h.AcceptSnapshot)
+		api.DELETE("/tests/:id/snapshots/:name", h.DeleteSnapshot)
+	}
+}
+
+// ListPendingSnapshots lists snapshots whose latest run differs from the baseline
+// GET /api/v2/snapshots/pending?limit=20&offset=0
+func (h *SnapshotHandler) ListPendingSnapshots(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	snapshots, total, err := h.snapshotService.ListPendingSnapshots(limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   snapshots,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ListSnapshots lists all snapshots of a test case
+// GET /api/v2/tests/:id/snapshots
+func (h *SnapshotHandler) ListSnapshots(c *gin.Context) {
+	testID := c.Param("id")
+
+	snapshots, err := h.snapshotService.ListSnapshots(testID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": snapshots})
+}
+
+// GetSnapshot retrieves a snapshot with its baseline, pending response and diff
+// GET /api/v2/tests/:id/snapshots/:name
+func (h *SnapshotHandler) GetSnapshot(c *gin.Context) {
+	testID := c.Param("id")
+	name := c.Param("name")
+
+	snapshot, err := h.snapshotService.GetSnapshot(testID, name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if snapshot == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "snapshot not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// AcceptSnapshot promotes the pending response to the new baseline
+// POST /api/v2/tests/:id/snapshots/:name/accept
+func (h *SnapshotHandler) AcceptSnapshot(c *gin.Context) {
+	testID := c.Param("id")
+	name := c.Param("name")
+
+	snapshot, err := h.snapshotService.AcceptSnapshot(testID, name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// DeleteSnapshot deletes a snapshot so that the next run records a new baseline
+// DELETE /api/v2/tests/:id/snapshots/:name
+func (h *SnapshotHandler) DeleteSnapshot(c *gin.Context) {
+	testID := c.Param("id")
+	name := c.Param("name")
+
+	if err := h.snapshotService.DeleteSnapshot(testID, name); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "snapshot deleted"})
+}
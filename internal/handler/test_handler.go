@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -48,11 +50,13 @@ func (h *TestHandler) RegisterRoutes(r *gin.Engine) {
 
 		// Test results
 		api.GET("/results/:id", h.GetTestResult)
+		api.GET("/results/:id/artifacts/:name", h.GetTestResultArtifact)
 		api.GET("/tests/:id/history", h.GetTestHistory)
 
 		// Test runs
 		api.GET("/runs/:id", h.GetTestRun)
 		api.GET("/runs", h.ListTestRuns)
+		api.POST("/runs/:id/cancel", h.CancelTestRun)
 	}
 }
 
@@ -224,7 +228,7 @@ func (h *TestHandler) GetTestGroupTree(c *gin.Context) {
 
 func (h *TestHandler) ExecuteTest(c *gin.Context) {
 	testID := c.Param("id")
-	result, err := h.service.ExecuteTest(testID)
+	result, err := h.service.ExecuteTest(c.Request.Context(), testID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -235,13 +239,36 @@ func (h *TestHandler) ExecuteTest(c *gin.Context) {
 
 func (h *TestHandler) ExecuteTestGroup(c *gin.Context) {
 	groupID := c.Param("id")
-	run, err := h.service.ExecuteTestGroup(groupID)
+
+	var options service.GroupRunOptions
+	if value := c.Query("concurrency"); value != "" {
+		concurrency, err := strconv.Atoi(value)
+		if err != nil || concurrency < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid concurrency"})
+			return
+		}
+		options.Concurrency = concurrency
+	}
+	if value := c.Query("recursive"); value != "" {
+		recursive, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid recursive"})
+			return
+		}
+		options.Recursive = recursive
+	}
+
+	run, err := h.service.QueueTestGroup(groupID, options)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrGroupNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, run)
+	c.JSON(http.StatusAccepted, run)
 }
 
 // ===== Test Result Handlers =====
@@ -267,6 +294,28 @@ func (h *TestHandler) GetTestResult(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+func (h *TestHandler) GetTestResultArtifact(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid result id"})
+		return
+	}
+
+	artifact, err := h.service.GetTestResultArtifact(uint(id), c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if artifact == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "artifact not found"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("result-%d-%s", id, artifact.Name)))
+	c.Data(http.StatusOK, artifact.ContentType, artifact.Content)
+}
+
 func (h *TestHandler) GetTestHistory(c *gin.Context) {
 	testID := c.Param("id")
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
@@ -315,6 +364,26 @@ func (h *TestHandler) ListTestRuns(c *gin.Context) {
 	})
 }
 
+// CancelTestRun cancels a queued or running test run
+func (h *TestHandler) CancelTestRun(c *gin.Context) {
+	runID := c.Param("id")
+	run, err := h.service.CancelTestRun(runID)
+	if err != nil {
+		c.JSON(cancelErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// cancelErrorStatus returns the HTTP status for an error cancelling a run
+func cancelErrorStatus(err error) int {
+	if errors.Is(err, service.ErrRunNotActive) {
+		return http.StatusConflict
+	}
+	return http.StatusNotFound
+}
+
 // ===== Web UI Specific Handlers =====
 
 // GetTestTree returns the complete test tree with groups and tests
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	gorillaws "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"test-management-service/internal/models"
+	"test-management-service/internal/repository"
+	"test-management-service/internal/service"
+	"test-management-service/internal/testcase"
+	"test-management-service/internal/websocket"
+)
+
+// newTestRouter returns a router serving the test API backed by an in-memory database; run
+// streams are served too if hub is set
+func newTestRouter(t *testing.T, hub *websocket.Hub) (*gin.Engine, *gorm.DB) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(&models.TestGroup{}, &models.TestCase{}, &models.TestResult{}, &models.TestArtifact{}, &models.TestRun{}))
+
+	svc := service.NewTestService(
+		repository.NewTestCaseRepository(db),
+		repository.NewTestGroupRepository(db),
+		repository.NewTestResultRepository(db),
+		repository.NewTestRunRepository(db),
+		testcase.NewExecutor("http://localhost"),
+		hub,
+		1,
+	)
+	r := gin.New()
+	NewTestHandler(svc).RegisterRoutes(r)
+	if hub != nil {
+		NewWebSocketHandler(hub).RegisterRoutes(r)
+	}
+	return r, db
+}
+
+// TestExecuteTestGroup_Accepted tests that executing a group queues a run and returns it with 202
+func TestExecuteTestGroup_Accepted(t *testing.T) {
+	r, db := newTestRouter(t, nil)
+	require.NoError(t, db.Create(&models.TestGroup{GroupID: "orders", Name: "Orders"}).Error)
+	for _, id := range []string{"orders-list", "orders-create"} {
+		require.NoError(t, db.Create(&models.TestCase{TestID: id, GroupID: "orders", Name: id, Type: "http"}).Error)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v2/groups/orders/execute?concurrency=2", nil))
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+
+	var run models.TestRun
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &run))
+	assert.NotEmpty(t, run.RunID)
+	assert.Equal(t, "queued", run.Status)
+	assert.Equal(t, "orders", run.GroupID)
+	assert.Equal(t, "Orders", run.Name)
+	assert.Equal(t, 2, run.Total)
+	assert.Zero(t, run.Completed)
+
+	// The queued run can be polled
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/runs/"+run.RunID, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var polled models.TestRun
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &polled))
+	assert.Equal(t, "queued", polled.Status)
+
+	for _, query := range []string{"concurrency=0", "concurrency=many", "recursive=maybe"} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v2/groups/orders/execute?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v2/groups/missing/execute", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error": "test group not found: missing"}`, w.Body.String())
+}
+
+// TestCancelTestRun tests that cancelling a run returns it and notifies its stream, and that
+// runs which are not in progress or do not exist are rejected
+func TestCancelTestRun(t *testing.T) {
+	hub := websocket.NewHub()
+	go hub.Run()
+	r, db := newTestRouter(t, hub)
+	require.NoError(t, db.Create(&models.TestGroup{GroupID: "search", Name: "Search"}).Error)
+	require.NoError(t, db.Create(&models.TestCase{TestID: "search-query", GroupID: "search", Name: "query", Type: "http"}).Error)
+
+	// No workers are started, so the run stays queued
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v2/groups/search/execute", nil))
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var run models.TestRun
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &run))
+
+	server := httptest.NewServer(r)
+	defer server.Close()
+	conn, _, err := gorillaws.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/v2/runs/"+run.RunID+"/stream", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	// The stream is subscribed once a message reaches it
+	subscribed := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-subscribed:
+				return
+			case <-time.After(10 * time.Millisecond):
+				hub.Broadcast(run.RunID, "ping", nil)
+			}
+		}
+	}()
+	var message websocket.Message
+	require.NoError(t, conn.ReadJSON(&message))
+	close(subscribed)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v2/runs/"+run.RunID+"/cancel", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var cancelled models.TestRun
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &cancelled))
+	assert.Equal(t, "cancelled", cancelled.Status)
+	assert.Equal(t, 1, cancelled.Skipped)
+
+	for message.Type != "run_cancelled" {
+		require.NoError(t, conn.ReadJSON(&message))
+	}
+	assert.Equal(t, run.RunID, message.RunID)
+	assert.Equal(t, "cancelled", message.Payload.(map[string]interface{})["status"])
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v2/runs/"+run.RunID+"/cancel", nil))
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "run is not in progress")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v2/runs/run-missing/cancel", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "test run not found: run-missing")
+}
@@ -33,6 +33,7 @@ func (h *WebSocketHandler) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/v2")
 	{
 		api.GET("/workflows/runs/:runId/stream", h.StreamWorkflowRun)
+		api.GET("/runs/:id/stream", h.StreamTestRun)
 	}
 }
 
@@ -44,6 +45,16 @@ func (h *WebSocketHandler) StreamWorkflowRun(c *gin.Context) {
 		return
 	}
 
+	h.stream(c, runID)
+}
+
+// StreamTestRun establishes WebSocket connection for test run
+func (h *WebSocketHandler) StreamTestRun(c *gin.Context) {
+	h.stream(c, c.Param("id"))
+}
+
+// stream upgrades the request and subscribes the connection to a run's messages
+func (h *WebSocketHandler) stream(c *gin.Context, runID string) {
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -38,6 +38,7 @@ func (h *WorkflowHandler) RegisterRoutes(r *gin.Engine) {
 		api.GET("/workflows/runs/:runId", h.GetWorkflowRun)
 		api.GET("/workflows/runs/:runId/steps", h.GetStepExecutions)
 		api.GET("/workflows/runs/:runId/logs", h.GetStepLogs)
+		api.POST("/workflows/runs/:runId/cancel", h.CancelWorkflowRun)
 
 		// Workflow relationships
 		api.GET("/workflows/:id/test-cases", h.GetWorkflowTestCases)
@@ -155,6 +156,18 @@ func (h *WorkflowHandler) GetWorkflowRun(c *gin.Context) {
 	c.JSON(http.StatusOK, run)
 }
 
+// CancelWorkflowRun cancels a workflow run in progress
+func (h *WorkflowHandler) CancelWorkflowRun(c *gin.Context) {
+	runID := c.Param("runId")
+	run, err := h.service.CancelWorkflowRun(runID)
+	if err != nil {
+		c.JSON(cancelErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
 // ListWorkflowRuns lists workflow execution runs
 func (h *WorkflowHandler) ListWorkflowRuns(c *gin.Context) {
 	workflowID := c.Param("id")
@@ -27,6 +27,27 @@ func (Environment) TableName() string {
 	return "environments"
 }
 
+// IsSecret reports whether the variable key is marked as secret by its environment variable record
+func (e *Environment) IsSecret(key string) bool {
+	for _, variable := range e.EnvironmentVariables {
+		if variable.Key == key && variable.IsSecret {
+			return true
+		}
+	}
+	return false
+}
+
+// SecretKeys returns the keys of the variables marked as secret
+func (e *Environment) SecretKeys() []string {
+	keys := []string{}
+	for _, variable := range e.EnvironmentVariables {
+		if variable.IsSecret {
+			keys = append(keys, variable.Key)
+		}
+	}
+	return keys
+}
+
 // EnvironmentVariable represents a single environment variable
 // Supports different types and secret masking for sensitive data
 type EnvironmentVariable struct {
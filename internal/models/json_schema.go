@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// JSONSchema 可复用的 JSON Schema 资源，供 json_schema 断言按 schemaId 引用
+type JSONSchema struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	SchemaID    string         `gorm:"uniqueIndex;size:100;not null" json:"schemaId"`
+	Name        string         `gorm:"size:255;not null" json:"name"`
+	Description string         `gorm:"type:text" json:"description,omitempty"`
+	Schema      JSONB          `gorm:"type:text;column:schema" json:"schema"` // schema 文档，默认 draft 2020-12
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName specifies the table name for JSONSchema model
+func (JSONSchema) TableName() string {
+	return "json_schemas"
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Snapshot 测试响应快照，snapshot 断言的基线（golden response）
+type Snapshot struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	TestID     string     `gorm:"size:255;not null;uniqueIndex:idx_snapshot_test_name" json:"testId"`
+	Name       string     `gorm:"size:100;not null;uniqueIndex:idx_snapshot_test_name" json:"name"` // 同一测试可有多个快照，默认 default
+	Status     string     `gorm:"size:50;not null;index" json:"status"`                             // accepted, pending
+	Baseline   JSONValue  `gorm:"type:text" json:"baseline"`                                        // 已接受的基线（已应用 ignore/normalize 规则）
+	Pending    JSONValue  `gorm:"type:text" json:"pending"`                                         // 最近一次与基线不一致的响应，待审核
+	Diff       JSONArray  `gorm:"type:text" json:"diff,omitempty"`                                  // Pending 与 Baseline 的差异
+	AcceptedAt *time.Time `json:"acceptedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Snapshot) TableName() string {
+	return "snapshots"
+}
+
+// JSONValue 任意 JSON 值（对象、数组或标量）
+type JSONValue struct {
+	Data interface{}
+}
+
+// IsNull 判断是否为空值
+func (j JSONValue) IsNull() bool {
+	return j.Data == nil
+}
+
+func (j JSONValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Data)
+}
+
+func (j *JSONValue) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &j.Data)
+}
+
+func (j JSONValue) Value() (driver.Value, error) {
+	if j.Data == nil {
+		return nil, nil
+	}
+	return json.Marshal(j.Data)
+}
+
+func (j *JSONValue) Scan(value interface{}) error {
+	if value == nil {
+		j.Data = nil
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("failed to unmarshal JSONValue value: unsupported type %T", value)
+	}
+	return json.Unmarshal(bytes, &j.Data)
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// TestArtifact 测试结果附件（如 HAR 请求记录），内容单独存储，TestResult.Artifacts 中只保留元数据
+type TestArtifact struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ResultID    uint      `gorm:"not null;uniqueIndex:idx_artifact_result_name" json:"resultId"`
+	Name        string    `gorm:"size:255;not null;uniqueIndex:idx_artifact_result_name" json:"name"` // 结果内唯一，如 requests.har
+	Type        string    `gorm:"size:50" json:"type"`                                                // har
+	ContentType string    `gorm:"size:100" json:"contentType"`
+	Size        int       `json:"size"`
+	Content     []byte    `json:"-"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (TestArtifact) TableName() string {
+	return "test_artifacts"
+}
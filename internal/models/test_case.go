@@ -20,6 +20,7 @@ type TestCase struct {
 	Status          string         `gorm:"size:50;default:'active';index" json:"status"` // active, inactive
 	Objective       string         `gorm:"type:text" json:"objective,omitempty"`
 	Timeout         int            `gorm:"default:300" json:"timeout,omitempty"` // seconds
+	Serial          bool           `gorm:"default:false" json:"serial,omitempty"` // 分组并发运行时单独执行
 
 	// Workflow integration support
 	WorkflowID      string         `gorm:"size:255;index" json:"workflowId,omitempty"`       // Mode 1: Reference workflow ID
@@ -45,6 +46,9 @@ type TestCase struct {
 	SetupHooks    JSONArray `gorm:"type:text;column:setup_hooks" json:"setupHooks,omitempty"`
 	TeardownHooks JSONArray `gorm:"type:text;column:teardown_hooks" json:"teardownHooks,omitempty"`
 
+	// 变量提取：name/type/path/group，提取值供清理钩子和同一分组运行中的后续测试以 {{name}} 引用
+	Extract JSONArray `gorm:"type:text;column:extract" json:"extract,omitempty"`
+
 	CreatedAt time.Time      `json:"createdAt"`
 	UpdatedAt time.Time      `json:"updatedAt"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
@@ -62,22 +66,26 @@ func (TestCase) TableName() string {
 
 // TestResult 测试执行结果模型
 type TestResult struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	TestID    string    `gorm:"size:255;not null;index" json:"testId"`
-	RunID     string    `gorm:"size:255;index" json:"runId,omitempty"`
-	Status    string    `gorm:"size:50;not null;index" json:"status"` // passed, failed, error, skipped
-	StartTime time.Time `gorm:"not null;index" json:"startTime"`
-	EndTime   time.Time `json:"endTime,omitempty"`
-	Duration  int       `json:"duration,omitempty"` // milliseconds
-	Error     string    `gorm:"type:text" json:"error,omitempty"`
-	Failures  JSONArray `gorm:"type:text" json:"failures,omitempty"`
-	Metrics   JSONB     `gorm:"type:text" json:"metrics,omitempty"`
-	Artifacts JSONArray `gorm:"type:text" json:"artifacts,omitempty"`
-	Logs      JSONArray `gorm:"type:text" json:"logs,omitempty"`
-	CreatedAt time.Time `json:"createdAt"`
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	TestID     string    `gorm:"size:255;not null;index" json:"testId"`
+	RunID      string    `gorm:"size:255;index" json:"runId,omitempty"`
+	Status     string    `gorm:"size:50;not null;index" json:"status"` // passed, failed, error, timeout, cancelled, skipped
+	StartTime  time.Time `gorm:"not null;index" json:"startTime"`
+	EndTime    time.Time `json:"endTime,omitempty"`
+	Duration   int       `json:"duration,omitempty"` // milliseconds
+	Error      string    `gorm:"type:text" json:"error,omitempty"`
+	Failures   JSONArray `gorm:"type:text" json:"failures,omitempty"`
+	Assertions JSONArray `gorm:"type:text" json:"assertions,omitempty"` // 每条断言的执行记录：type/path/operator/expected/actual/passed/soft/message
+	Metrics    JSONB     `gorm:"type:text" json:"metrics,omitempty"`
+	Response   JSONB     `gorm:"type:text" json:"response,omitempty"`  // 响应快照：HTTP 为 statusCode/headers/cookies/responseTimeMs/body，命令为 exitCode/stdout/stderr
+	Artifacts  JSONArray `gorm:"type:text" json:"artifacts,omitempty"` // 附件元数据：name/type/contentType/size，内容见 TestArtifact
+	Logs       JSONArray `gorm:"type:text" json:"logs,omitempty"`
+	Variables  JSONB     `gorm:"type:text" json:"variables,omitempty"` // 测试提取的变量
+	CreatedAt  time.Time `json:"createdAt"`
 
 	// 关联
-	TestCase *TestCase `gorm:"foreignKey:TestID;references:TestID" json:"-"`
+	TestCase      *TestCase      `gorm:"foreignKey:TestID;references:TestID" json:"-"`
+	ArtifactFiles []TestArtifact `gorm:"foreignKey:ResultID" json:"-"` // 随结果一起创建
 }
 
 // TableName 指定表名
@@ -87,20 +95,25 @@ func (TestResult) TableName() string {
 
 // TestRun 测试批次执行模型
 type TestRun struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	RunID     string    `gorm:"uniqueIndex;size:255;not null" json:"runId"`
-	Name      string    `gorm:"size:255" json:"name,omitempty"`
-	Total     int       `gorm:"default:0" json:"total"`
-	Passed    int       `gorm:"default:0" json:"passed"`
-	Failed    int       `gorm:"default:0" json:"failed"`
-	Errors    int       `gorm:"default:0" json:"errors"`
-	Skipped   int       `gorm:"default:0" json:"skipped"`
-	StartTime time.Time `gorm:"index" json:"startTime,omitempty"`
-	EndTime   time.Time `json:"endTime,omitempty"`
-	Duration  int       `json:"duration,omitempty"` // milliseconds
-	Status    string    `gorm:"size:50;default:'running';index" json:"status"` // running, completed, cancelled
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID          uint             `gorm:"primaryKey" json:"id"`
+	RunID       string           `gorm:"uniqueIndex;size:255;not null" json:"runId"`
+	Name        string           `gorm:"size:255" json:"name,omitempty"`
+	GroupID     string           `gorm:"size:255;index" json:"groupId,omitempty"`
+	Total       int              `gorm:"default:0" json:"total"`
+	Passed      int              `gorm:"default:0" json:"passed"`
+	Failed      int              `gorm:"default:0" json:"failed"`
+	Errors      int              `gorm:"default:0" json:"errors"`
+	Skipped     int              `gorm:"default:0" json:"skipped"`
+	Completed   int              `gorm:"default:0" json:"completed"`            // 已结束（含跳过）的测试数
+	CurrentTest string           `gorm:"size:255" json:"currentTest,omitempty"` // 最近开始且仍在执行的测试
+	StartTime   time.Time        `gorm:"index" json:"startTime,omitempty"`
+	EndTime     time.Time        `json:"endTime,omitempty"`
+	Duration    int              `json:"duration,omitempty"`                            // milliseconds
+	Status      string           `gorm:"size:50;default:'running';index" json:"status"` // queued, running, completed, timeout, cancelled, interrupted, error
+	Error       string           `gorm:"type:text" json:"error,omitempty"`              // 分组钩子失败等运行级错误
+	Summary     *GroupRunSummary `gorm:"type:text" json:"summary,omitempty"`            // 递归运行时按分组汇总的统计
+	CreatedAt   time.Time        `json:"createdAt"`
+	UpdatedAt   time.Time        `json:"updatedAt"`
 
 	// 关联
 	Results []TestResult `gorm:"foreignKey:RunID;references:RunID" json:"results,omitempty"`
@@ -111,6 +124,40 @@ func (TestRun) TableName() string {
 	return "test_runs"
 }
 
+// GroupRunSummary 递归分组运行中一个分组的统计，计数包含其所有子分组
+type GroupRunSummary struct {
+	GroupID  string             `json:"groupId"`
+	Name     string             `json:"name,omitempty"`
+	Total    int                `json:"total"`
+	Passed   int                `json:"passed"`
+	Failed   int                `json:"failed"`
+	Errors   int                `json:"errors"`
+	Skipped  int                `json:"skipped"`
+	Error    string             `json:"error,omitempty"` // 本分组钩子失败信息
+	Children []*GroupRunSummary `json:"children,omitempty"`
+}
+
+func (s GroupRunSummary) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+func (s *GroupRunSummary) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("failed to unmarshal GroupRunSummary value: unsupported type %T", value)
+	}
+	return json.Unmarshal(bytes, s)
+}
+
 // ===== 自定义JSON类型 =====
 
 // JSONB 自定义JSON类型（用于对象）
@@ -1,26 +1,29 @@
 package models
 
 import (
-	"time"
 	"gorm.io/gorm"
+	"time"
 )
 
 // TestGroup 测试分组模型
 type TestGroup struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	GroupID     string         `gorm:"uniqueIndex;size:255;not null" json:"groupId"`
-	ParentID    string         `gorm:"size:255;index" json:"parentId,omitempty"`
-	Name        string         `gorm:"size:255;not null" json:"name"`
-	Description string         `gorm:"type:text" json:"description,omitempty"`
-	TargetHost  string         `gorm:"size:512" json:"targetHost,omitempty"` // 测试目标服务地址
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	GroupID      string `gorm:"uniqueIndex;size:255;not null" json:"groupId"`
+	ParentID     string `gorm:"size:255;index" json:"parentId,omitempty"`
+	Name         string `gorm:"size:255;not null" json:"name"`
+	Description  string `gorm:"type:text" json:"description,omitempty"`
+	TargetHost   string `gorm:"size:512" json:"targetHost,omitempty"`                   // 测试目标服务地址
+	Timeout      int    `gorm:"default:0" json:"timeout,omitempty"`                     // 分组运行超时（秒），0 表示不限制
+	TLSConfig    JSONB  `gorm:"type:text;column:tls_config" json:"tlsConfig,omitempty"` // TLS 客户端配置：caCert/clientCert/clientKey/serverName/insecureSkipVerify
+	ShareCookies bool   `gorm:"default:false" json:"shareCookies,omitempty"`            // 分组运行内的测试共享同一个 Cookie 会话
 
 	// Lifecycle hooks for group-level setup/teardown
 	SetupHooks    JSONArray `gorm:"type:text;column:setup_hooks" json:"setupHooks,omitempty"`
 	TeardownHooks JSONArray `gorm:"type:text;column:teardown_hooks" json:"teardownHooks,omitempty"`
 
-	CreatedAt   time.Time      `json:"createdAt"`
-	UpdatedAt   time.Time      `json:"updatedAt"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// 关联
 	Children  []TestGroup `gorm:"foreignKey:ParentID;references:GroupID" json:"children,omitempty"`
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"test-management-service/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// JSONSchemaRepository defines the interface for reusable JSON schema data access
+type JSONSchemaRepository interface {
+	// Create creates a new schema
+	Create(schema *models.JSONSchema) error
+
+	// Update updates an existing schema
+	Update(schema *models.JSONSchema) error
+
+	// Delete soft-deletes a schema by schemaID
+	Delete(schemaID string) error
+
+	// FindByID retrieves a schema by schemaID, returning nil if it does not exist
+	FindByID(schemaID string) (*models.JSONSchema, error)
+
+	// FindAll retrieves all schemas with pagination
+	// Returns schemas slice, total count, and error
+	FindAll(limit, offset int) ([]models.JSONSchema, int64, error)
+
+	// GetSchema retrieves a schema by schemaID for test execution, failing if it does not exist
+	GetSchema(schemaID string) (*models.JSONSchema, error)
+}
+
+// jsonSchemaRepository implements JSONSchemaRepository interface
+type jsonSchemaRepository struct {
+	db *gorm.DB
+}
+
+// NewJSONSchemaRepository creates a new JSONSchemaRepository instance
+func NewJSONSchemaRepository(db *gorm.DB) JSONSchemaRepository {
+	return &jsonSchemaRepository{db: db}
+}
+
+// Create creates a new schema in the database
+func (r *jsonSchemaRepository) Create(schema *models.JSONSchema) error {
+	return r.db.Create(schema).Error
+}
+
+// Update updates an existing schema in the database
+func (r *jsonSchemaRepository) Update(schema *models.JSONSchema) error {
+	return r.db.Save(schema).Error
+}
+
+// Delete soft-deletes a schema by schemaID
+func (r *jsonSchemaRepository) Delete(schemaID string) error {
+	return r.db.Where("schema_id = ?", schemaID).Delete(&models.JSONSchema{}).Error
+}
+
+// FindByID retrieves a schema by schemaID
+func (r *jsonSchemaRepository) FindByID(schemaID string) (*models.JSONSchema, error) {
+	var schema models.JSONSchema
+	err := r.db.Where("schema_id = ?", schemaID).First(&schema).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// FindAll retrieves all schemas with pagination
+func (r *jsonSchemaRepository) FindAll(limit, offset int) ([]models.JSONSchema, int64, error) {
+	var schemas []models.JSONSchema
+	var total int64
+
+	if err := r.db.Model(&models.JSONSchema{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.db.Order("schema_id").Limit(limit).Offset(offset).Find(&schemas).Error
+	return schemas, total, err
+}
+
+// GetSchema retrieves a schema by schemaID for test execution
+func (r *jsonSchemaRepository) GetSchema(schemaID string) (*models.JSONSchema, error) {
+	schema, err := r.FindByID(schemaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema: %w", err)
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("schema not found: %s", schemaID)
+	}
+	return schema, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"errors"
+
+	"test-management-service/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SnapshotRepository defines the interface for response snapshot data access
+type SnapshotRepository interface {
+	// FindByTestID retrieves all snapshots of a test case
+	FindByTestID(testID string) ([]models.Snapshot, error)
+
+	// FindByStatus retrieves snapshots with the given status with pagination
+	// Returns snapshots slice, total count, and error
+	FindByStatus(status string, limit, offset int) ([]models.Snapshot, int64, error)
+
+	// Delete deletes a snapshot so that the next run records a new baseline
+	Delete(testID, name string) error
+
+	// GetSnapshot retrieves a snapshot by test ID and name, returning nil if it does not exist
+	GetSnapshot(testID, name string) (*models.Snapshot, error)
+
+	// SaveSnapshot creates or updates a snapshot
+	SaveSnapshot(snapshot *models.Snapshot) error
+}
+
+// snapshotRepository implements SnapshotRepository interface
+type snapshotRepository struct {
+	db *gorm.DB
+}
+
+// NewSnapshotRepository creates a new SnapshotRepository instance
+func NewSnapshotRepository(db *gorm.DB) SnapshotRepository {
+	return &snapshotRepository{db: db}
+}
+
+// FindByTestID retrieves all snapshots of a test case ordered by name
+func (r *snapshotRepository) FindByTestID(testID string) ([]models.Snapshot, error) {
+	var snapshots []models.Snapshot
+	err := r.db.Where("test_id = ?", testID).Order("name").Find(&snapshots).Error
+	return snapshots, err
+}
+
+// FindByStatus retrieves snapshots with the given status with pagination
+func (r *snapshotRepository) FindByStatus(status string, limit, offset int) ([]models.Snapshot, int64, error) {
+	var snapshots []models.Snapshot
+	var total int64
+
+	query := r.db.Model(&models.Snapshot{}).Where("status = ?", status)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("updated_at DESC").Limit(limit).Offset(offset).Find(&snapshots).Error
+	return snapshots, total, err
+}
+
+// Delete deletes a snapshot by test ID and name
+func (r *snapshotRepository) Delete(testID, name string) error {
+	return r.db.Where("test_id = ? AND name = ?", testID, name).Delete(&models.Snapshot{}).Error
+}
+
+// GetSnapshot retrieves a snapshot by test ID and name
+func (r *snapshotRepository) GetSnapshot(testID, name string) (*models.Snapshot, error) {
+	var snapshot models.Snapshot
+	err := r.db.Where("test_id = ? AND name = ?", testID, name).First(&snapshot).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// SaveSnapshot creates or updates a snapshot
+func (r *snapshotRepository) SaveSnapshot(snapshot *models.Snapshot) error {
+	return r.db.Save(snapshot).Error
+}
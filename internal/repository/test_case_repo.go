@@ -56,7 +56,7 @@ func (r *testCaseRepo) FindByID(testID string) (*models.TestCase, error) {
 
 func (r *testCaseRepo) FindByGroupID(groupID string) ([]models.TestCase, error) {
 	var testCases []models.TestCase
-	err := r.db.Where("group_id = ?", groupID).Find(&testCases).Error
+	err := r.db.Where("group_id = ?", groupID).Order("id").Find(&testCases).Error
 	return testCases, err
 }
 
@@ -54,7 +54,7 @@ func (r *testGroupRepo) FindByID(groupID string) (*models.TestGroup, error) {
 
 func (r *testGroupRepo) FindByParentID(parentID string) ([]models.TestGroup, error) {
 	var groups []models.TestGroup
-	err := r.db.Where("parent_id = ?", parentID).Find(&groups).Error
+	err := r.db.Where("parent_id = ?", parentID).Order("id").Find(&groups).Error
 	return groups, err
 }
 
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"errors"
 	"test-management-service/internal/models"
 	"time"
 
@@ -13,6 +14,7 @@ type TestResultRepository interface {
 	FindByID(id uint) (*models.TestResult, error)
 	FindByTestID(testID string, limit int) ([]models.TestResult, error)
 	FindByRunID(runID string) ([]models.TestResult, error)
+	FindArtifact(resultID uint, name string) (*models.TestArtifact, error)
 	DeleteOlderThan(days int) error
 }
 
@@ -53,8 +55,24 @@ func (r *testResultRepo) FindByRunID(runID string) ([]models.TestResult, error)
 	return results, err
 }
 
+func (r *testResultRepo) FindArtifact(resultID uint, name string) (*models.TestArtifact, error) {
+	var artifact models.TestArtifact
+	err := r.db.Where("result_id = ? AND name = ?", resultID, name).First(&artifact).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &artifact, nil
+}
+
 func (r *testResultRepo) DeleteOlderThan(days int) error {
 	cutoff := time.Now().AddDate(0, 0, -days)
+	oldResults := r.db.Model(&models.TestResult{}).Select("id").Where("created_at < ?", cutoff)
+	if err := r.db.Where("result_id IN (?)", oldResults).Delete(&models.TestArtifact{}).Error; err != nil {
+		return err
+	}
 	return r.db.Where("created_at < ?", cutoff).Delete(&models.TestResult{}).Error
 }
 
@@ -64,6 +82,7 @@ type TestRunRepository interface {
 	Update(run *models.TestRun) error
 	FindByID(runID string) (*models.TestRun, error)
 	FindAll(limit, offset int) ([]models.TestRun, int64, error)
+	FindByStatus(statuses ...string) ([]models.TestRun, error)
 }
 
 type testRunRepo struct {
@@ -102,3 +121,9 @@ func (r *testRunRepo) FindAll(limit, offset int) ([]models.TestRun, int64, error
 	err := r.db.Order("created_at DESC").Limit(limit).Offset(offset).Find(&runs).Error
 	return runs, total, err
 }
+
+func (r *testRunRepo) FindByStatus(statuses ...string) ([]models.TestRun, error) {
+	var runs []models.TestRun
+	err := r.db.Where("status IN ?", statuses).Order("id").Find(&runs).Error
+	return runs, err
+}
@@ -22,10 +22,13 @@ type EnvironmentService interface {
 	// Variable Management
 	GetVariables(envID string) (map[string]interface{}, error)
 	GetVariable(envID, key string) (interface{}, error)
-	SetVariable(envID, key string, value interface{}) error
+	SetVariable(envID, key string, value interface{}, secret *bool) error
 	DeleteVariable(envID, key string) error
 }
 
+// SecretMask 替代密钥变量值返回给客户端；更新时提交该值表示保留原值
+const SecretMask = "******"
+
 type environmentService struct {
 	envRepo    repository.EnvironmentRepository
 	envVarRepo repository.EnvironmentVariableRepository
@@ -49,16 +52,19 @@ type CreateEnvironmentRequest struct {
 	Name        string                 `json:"name" binding:"required"`
 	Description string                 `json:"description"`
 	Variables   map[string]interface{} `json:"variables"`
+	SecretKeys  []string               `json:"secretKeys"`
 }
 
 type UpdateEnvironmentRequest struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
 	Variables   map[string]interface{} `json:"variables"`
+	SecretKeys  []string               `json:"secretKeys"` // nil 表示不修改
 }
 
 type SetVariableRequest struct {
-	Value interface{} `json:"value" binding:"required"`
+	Value  interface{} `json:"value" binding:"required"`
+	Secret *bool       `json:"secret"` // nil 表示保持原有设置
 }
 
 // ===== Implementation =====
@@ -77,6 +83,12 @@ func (s *environmentService) CreateEnvironment(req *CreateEnvironmentRequest) (*
 		IsActive:    false, // 新环境默认不激活
 		Variables:   models.JSONB(req.Variables),
 	}
+	// 密钥标记保存在环境变量记录的 is_secret 字段，随环境一起创建
+	for _, key := range req.SecretKeys {
+		if !env.IsSecret(key) {
+			env.EnvironmentVariables = append(env.EnvironmentVariables, models.EnvironmentVariable{EnvID: req.EnvID, Key: key, IsSecret: true})
+		}
+	}
 
 	if err := s.envRepo.Create(env); err != nil {
 		return nil, fmt.Errorf("failed to create environment: %w", err)
@@ -97,7 +109,32 @@ func (s *environmentService) UpdateEnvironment(envID string, req *UpdateEnvironm
 	if req.Description != "" {
 		env.Description = req.Description
 	}
+	if req.SecretKeys != nil {
+		secret := make(map[string]bool, len(req.SecretKeys))
+		for _, key := range req.SecretKeys {
+			secret[key] = true
+			if err := s.setSecret(env, key, true); err != nil {
+				return nil, err
+			}
+		}
+		for _, variable := range env.EnvironmentVariables {
+			if !secret[variable.Key] {
+				if err := s.setSecret(env, variable.Key, false); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := s.reloadVariableRecords(env); err != nil {
+			return nil, err
+		}
+	}
 	if req.Variables != nil {
+		// 密钥变量提交掩码时保留原值
+		for key, value := range req.Variables {
+			if value == SecretMask && env.IsSecret(key) {
+				req.Variables[key] = env.Variables[key]
+			}
+		}
 		env.Variables = models.JSONB(req.Variables)
 	}
 
@@ -132,7 +169,7 @@ func (s *environmentService) ListEnvironments(limit, offset int) ([]models.Envir
 
 func (s *environmentService) GetActiveEnvironment() (*models.Environment, error) {
 	env, err := s.envRepo.FindActive()
-	if err != nil {
+	if err != nil || env == nil {
 		return nil, fmt.Errorf("no active environment found")
 	}
 	return env, nil
@@ -148,13 +185,14 @@ func (s *environmentService) ActivateEnvironment(envID string) error {
 	return s.envRepo.SetActive(envID)
 }
 
+// GetVariables 返回环境变量，密钥变量的值以掩码返回
 func (s *environmentService) GetVariables(envID string) (map[string]interface{}, error) {
 	env, err := s.envRepo.FindByID(envID)
 	if err != nil {
 		return nil, fmt.Errorf("environment not found: %s", envID)
 	}
 
-	return env.Variables, nil
+	return MaskSecrets(env).Variables, nil
 }
 
 func (s *environmentService) GetVariable(envID, key string) (interface{}, error) {
@@ -171,7 +209,7 @@ func (s *environmentService) GetVariable(envID, key string) (interface{}, error)
 	return value, nil
 }
 
-func (s *environmentService) SetVariable(envID, key string, value interface{}) error {
+func (s *environmentService) SetVariable(envID, key string, value interface{}, secret *bool) error {
 	env, err := s.envRepo.FindByID(envID)
 	if err != nil {
 		return fmt.Errorf("environment not found: %s", envID)
@@ -181,6 +219,18 @@ func (s *environmentService) SetVariable(envID, key string, value interface{}) e
 		env.Variables = make(models.JSONB)
 	}
 
+	if value == SecretMask && (env.IsSecret(key) || (secret != nil && *secret)) {
+		// 掩码表示只修改密钥标记，保留原值
+		value = env.Variables[key]
+	}
+	if secret != nil {
+		if err := s.setSecret(env, key, *secret); err != nil {
+			return err
+		}
+		if err := s.reloadVariableRecords(env); err != nil {
+			return err
+		}
+	}
 	env.Variables[key] = value
 
 	return s.envRepo.Update(env)
@@ -197,6 +247,66 @@ func (s *environmentService) DeleteVariable(envID, key string) error {
 	}
 
 	delete(env.Variables, key)
+	if err := s.setSecret(env, key, false); err != nil {
+		return err
+	}
+	if err := s.reloadVariableRecords(env); err != nil {
+		return err
+	}
 
 	return s.envRepo.Update(env)
 }
+
+// MaskSecrets 返回环境的副本，密钥变量的值替换为掩码
+func MaskSecrets(env *models.Environment) *models.Environment {
+	if env == nil || len(env.SecretKeys()) == 0 {
+		return env
+	}
+
+	masked := *env
+	masked.Variables = make(models.JSONB, len(env.Variables))
+	for key, value := range env.Variables {
+		if env.IsSecret(key) {
+			value = SecretMask
+		}
+		masked.Variables[key] = value
+	}
+	masked.EnvironmentVariables = make([]models.EnvironmentVariable, len(env.EnvironmentVariables))
+	for i, variable := range env.EnvironmentVariables {
+		if variable.IsSecret && variable.Value != "" {
+			variable.Value = SecretMask
+		}
+		masked.EnvironmentVariables[i] = variable
+	}
+	return &masked
+}
+
+// setSecret 通过环境变量记录的 is_secret 字段标记或取消标记密钥变量
+func (s *environmentService) setSecret(env *models.Environment, key string, secret bool) error {
+	variable, err := s.envVarRepo.FindByKey(env.EnvID, key)
+	if err != nil {
+		return fmt.Errorf("failed to find variable '%s': %w", key, err)
+	}
+
+	switch {
+	case variable == nil && secret:
+		err = s.envVarRepo.Create(&models.EnvironmentVariable{EnvID: env.EnvID, Key: key, IsSecret: true})
+	case variable != nil && variable.IsSecret != secret:
+		variable.IsSecret = secret
+		err = s.envVarRepo.Update(variable)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update variable '%s': %w", key, err)
+	}
+	return nil
+}
+
+// reloadVariableRecords 重新加载环境变量记录，使环境反映最新的密钥标记
+func (s *environmentService) reloadVariableRecords(env *models.Environment) error {
+	variables, err := s.envVarRepo.FindByEnvID(env.EnvID)
+	if err != nil {
+		return fmt.Errorf("failed to load variables of environment '%s': %w", env.EnvID, err)
+	}
+	env.EnvironmentVariables = variables
+	return nil
+}
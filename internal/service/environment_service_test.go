@@ -0,0 +1,97 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"test-management-service/internal/models"
+	"test-management-service/internal/repository"
+)
+
+// openTestDB opens an in-memory database private to the test, shared by its connections
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", name)), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	require.NoError(t, db.AutoMigrate(
+		&models.TestGroup{},
+		&models.TestCase{},
+		&models.TestResult{},
+		&models.TestRun{},
+		&models.TestArtifact{},
+		&models.Environment{},
+		&models.EnvironmentVariable{},
+	))
+	return db
+}
+
+// TestEnvironmentService_SecretVariables tests that secret marks are stored in the is_secret
+// column of the environment's variable records
+func TestEnvironmentService_SecretVariables(t *testing.T) {
+	db := openTestDB(t)
+	envVarRepo := repository.NewEnvironmentVariableRepository(db)
+	svc := NewEnvironmentService(repository.NewEnvironmentRepository(db), envVarRepo)
+
+	_, err := svc.CreateEnvironment(&CreateEnvironmentRequest{
+		EnvID:      "dev",
+		Name:       "Development",
+		Variables:  map[string]interface{}{"BASE_URL": "http://localhost", "API_KEY": "key-1"},
+		SecretKeys: []string{"API_KEY"},
+	})
+	require.NoError(t, err)
+
+	record, err := envVarRepo.FindByKey("dev", "API_KEY")
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.True(t, record.IsSecret)
+
+	vars, err := svc.GetVariables("dev")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"BASE_URL": "http://localhost", "API_KEY": SecretMask}, vars)
+
+	// Secrecy comes from the record, not the variable name
+	secret := true
+	require.NoError(t, svc.SetVariable("dev", "BASE_URL", "http://internal", &secret))
+	require.NoError(t, svc.SetVariable("dev", "API_KEY", SecretMask, nil))
+	require.NoError(t, db.Model(&models.EnvironmentVariable{}).Where("env_id = ? AND key = ?", "dev", "API_KEY").Update("is_secret", false).Error)
+
+	env, err := svc.GetEnvironment("dev")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BASE_URL"}, env.SecretKeys())
+	assert.Equal(t, "key-1", env.Variables["API_KEY"])
+	assert.Equal(t, models.JSONB{"BASE_URL": SecretMask, "API_KEY": "key-1"}, MaskSecrets(env).Variables)
+
+	// The injector masks command output using the active environment's records
+	require.NoError(t, svc.ActivateEnvironment("dev"))
+	keys, err := NewVariableInjector(svc).GetActiveEnvironmentSecretKeys()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BASE_URL"}, keys)
+
+	// Updating the secret keys replaces the marks; deleting a variable clears its mark
+	_, err = svc.UpdateEnvironment("dev", &UpdateEnvironmentRequest{SecretKeys: []string{"API_KEY"}})
+	require.NoError(t, err)
+	env, err = svc.GetEnvironment("dev")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"API_KEY"}, env.SecretKeys())
+
+	require.NoError(t, svc.DeleteVariable("dev", "API_KEY"))
+	env, err = svc.GetEnvironment("dev")
+	require.NoError(t, err)
+	assert.Empty(t, env.SecretKeys())
+	assert.NotContains(t, env.Variables, "API_KEY")
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"test-management-service/internal/models"
+	"test-management-service/internal/testcase"
+)
+
+// groupRunner 执行分组运行中的测试，并按测试顺序保存结果、更新运行统计和进度
+type groupRunner struct {
+	service   *testService
+	executor  *testcase.UnifiedTestExecutor
+	run       *models.TestRun
+	summaries []*models.GroupRunSummary // 递归运行时结果同时计入的分组统计
+
+	mu      sync.Mutex
+	results []*testcase.TestResult // 已完成但尚未保存的结果，按测试位置存放
+	done    []bool
+	saved   int // 已按顺序处理的测试数
+}
+
+func newGroupRunner(s *testService, executor *testcase.UnifiedTestExecutor, run *models.TestRun, summaries []*models.GroupRunSummary, total int) *groupRunner {
+	return &groupRunner{
+		service:   s,
+		executor:  executor,
+		run:       run,
+		summaries: summaries,
+		results:   make([]*testcase.TestResult, total),
+		done:      make([]bool, total),
+	}
+}
+
+// execute runs the tests with up to concurrency tests in flight. A serial test waits for the
+// tests started before it and runs alone. Tests are skipped once ctx is done or if skip is set.
+func (r *groupRunner) execute(ctx context.Context, tests []models.TestCase, concurrency int, skip bool) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range tests {
+		tc := &tests[i]
+		if tc.Serial {
+			wg.Wait()
+		}
+		if skip || ctx.Err() != nil {
+			r.record(i, nil)
+			continue
+		}
+
+		execTC := r.service.convertToExecutorTestCase(tc)
+		r.start(tc.TestID)
+		if tc.Serial || concurrency == 1 {
+			r.record(i, r.executor.ExecuteContext(ctx, execTC))
+			continue
+		}
+
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			r.record(i, r.executor.ExecuteContext(ctx, execTC))
+		}(i)
+	}
+	wg.Wait()
+}
+
+// start records that a test has started as the run's current test
+func (r *groupRunner) start(testID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.run.CurrentTest = testID
+	r.saveProgress()
+}
+
+// record stores the result of the test at position i (nil if it was skipped), then saves the
+// results of all consecutive completed tests so they are stored in test order
+func (r *groupRunner) record(i int, result *testcase.TestResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.results[i] = result
+	r.done[i] = true
+	for r.saved < len(r.done) && r.done[r.saved] {
+		r.save(r.results[r.saved])
+		r.results[r.saved] = nil
+		r.saved++
+	}
+
+	r.run.Completed++
+	if result != nil && r.run.CurrentTest == result.TestID {
+		r.run.CurrentTest = ""
+	}
+	r.saveProgress()
+}
+
+// saveProgress stores the run's counters and current test so that they can be polled while it runs
+func (r *groupRunner) saveProgress() {
+	if err := r.service.runRepo.Update(r.run); err != nil {
+		fmt.Printf("failed to save progress of test run %s: %v\n", r.run.RunID, err)
+	}
+}
+
+// save stores a result and counts it in the run and group statistics; skipped tests have no result
+func (r *groupRunner) save(result *testcase.TestResult) {
+	status := "skipped"
+	if result != nil {
+		dbResult := r.service.convertToModelResult(result)
+		dbResult.RunID = r.run.RunID
+		if err := r.service.resultRepo.Create(dbResult); err != nil {
+			fmt.Printf("failed to save result for test %s: %v\n", result.TestID, err)
+			return
+		}
+		status = result.Status
+	}
+
+	countStatus(status, &r.run.Passed, &r.run.Failed, &r.run.Errors, &r.run.Skipped)
+	for _, summary := range r.summaries {
+		countStatus(status, &summary.Passed, &summary.Failed, &summary.Errors, &summary.Skipped)
+	}
+}
+
+// countStatus adds a test outcome to a set of statistics
+func countStatus(status string, passed, failed, errors, skipped *int) {
+	switch status {
+	case "passed":
+		*passed++
+	case "failed":
+		*failed++
+	case "error", "timeout":
+		*errors++
+	case "skipped", "cancelled":
+		*skipped++
+	}
+}
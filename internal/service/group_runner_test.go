@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"test-management-service/internal/models"
+	"test-management-service/internal/repository"
+	"test-management-service/internal/testcase"
+)
+
+// requestEvent is the start or end of a request to a loadServer
+type requestEvent struct {
+	path  string
+	start bool
+}
+
+// loadServer answers /<name>?delay=<ms>&status=<code> and records the requests in flight
+type loadServer struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	events      []requestEvent
+}
+
+func newLoadServer(t *testing.T) *loadServer {
+	s := &loadServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.inFlight++
+		if s.inFlight > s.maxInFlight {
+			s.maxInFlight = s.inFlight
+		}
+		s.events = append(s.events, requestEvent{path: r.URL.Path, start: true})
+		s.mu.Unlock()
+
+		delay, _ := strconv.Atoi(r.URL.Query().Get("delay"))
+		select {
+		case <-time.After(time.Duration(delay) * time.Millisecond):
+		case <-r.Context().Done():
+		}
+
+		s.mu.Lock()
+		s.inFlight--
+		s.events = append(s.events, requestEvent{path: r.URL.Path})
+		s.mu.Unlock()
+
+		if status, _ := strconv.Atoi(r.URL.Query().Get("status")); status != 0 {
+			w.WriteHeader(status)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// index returns the position of a request's start or end event, or -1
+func (s *loadServer) index(path string, start bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, event := range s.events {
+		if event.path == path && event.start == start {
+			return i
+		}
+	}
+	return -1
+}
+
+// newRunService returns a test service executing tests against baseURL
+func newRunService(t *testing.T, baseURL string, concurrency int) (*testService, *gorm.DB) {
+	db := openTestDB(t)
+	svc := NewTestService(
+		repository.NewTestCaseRepository(db),
+		repository.NewTestGroupRepository(db),
+		repository.NewTestResultRepository(db),
+		repository.NewTestRunRepository(db),
+		testcase.NewExecutor(baseURL),
+		nil,
+		concurrency,
+	)
+	return svc.(*testService), db
+}
+
+// createGroup stores a group with an HTTP test per path; tests expect a 200 response
+func createGroup(t *testing.T, db *gorm.DB, group *models.TestGroup, paths ...string) {
+	t.Helper()
+	if group.Name == "" {
+		group.Name = group.GroupID
+	}
+	require.NoError(t, db.Create(group).Error)
+	for _, path := range paths {
+		require.NoError(t, db.Create(httpTest(group.GroupID, path)).Error)
+	}
+}
+
+// httpTest returns an HTTP test of a group named after the path it requests
+func httpTest(groupID, path string) *models.TestCase {
+	name, _, _ := strings.Cut(path[1:], "?")
+	return &models.TestCase{
+		TestID:     groupID + "-" + name,
+		GroupID:    groupID,
+		Name:       name,
+		Type:       "http",
+		HTTPConfig: models.JSONB{"method": "GET", "path": path},
+		Assertions: models.JSONArray{map[string]interface{}{"type": "status_code", "expected": 200}},
+	}
+}
+
+// savedTestIDs returns the test IDs of a run's results in the order they were saved
+func savedTestIDs(t *testing.T, db *gorm.DB, runID string) []string {
+	t.Helper()
+	var results []models.TestResult
+	require.NoError(t, db.Where("run_id = ?", runID).Order("id").Find(&results).Error)
+	ids := make([]string, len(results))
+	for i, result := range results {
+		ids[i] = result.TestID
+	}
+	return ids
+}
+
+// TestGroupRunner_ConcurrencyAndOrder tests that no more tests than the limit run at once and
+// that results are saved in definition order when later tests finish first
+func TestGroupRunner_ConcurrencyAndOrder(t *testing.T) {
+	server := newLoadServer(t)
+	svc, db := newRunService(t, server.URL, 1)
+	createGroup(t, db, &models.TestGroup{GroupID: "orders"},
+		"/t1?delay=160", "/t2?delay=140", "/t3?delay=120&status=500", "/t4?delay=100",
+		"/t5?delay=80", "/t6?delay=60", "/t7?delay=40", "/t8?delay=20")
+
+	run, err := svc.ExecuteTestGroup(context.Background(), "orders", GroupRunOptions{Concurrency: 3})
+	require.NoError(t, err)
+
+	server.mu.Lock()
+	maxInFlight := server.maxInFlight
+	server.mu.Unlock()
+	assert.LessOrEqual(t, maxInFlight, 3)
+	assert.GreaterOrEqual(t, maxInFlight, 2, "tests should run concurrently")
+
+	assert.Equal(t, []string{
+		"orders-t1", "orders-t2", "orders-t3", "orders-t4",
+		"orders-t5", "orders-t6", "orders-t7", "orders-t8",
+	}, savedTestIDs(t, db, run.RunID))
+
+	stored, err := svc.GetTestRun(run.RunID)
+	require.NoError(t, err)
+	assert.Equal(t, "completed", stored.Status)
+	assert.Equal(t, 8, stored.Total)
+	assert.Equal(t, 8, stored.Completed)
+	assert.Equal(t, 7, stored.Passed)
+	assert.Equal(t, 1, stored.Failed)
+	assert.Equal(t, 0, stored.Errors)
+	assert.Equal(t, 0, stored.Skipped)
+	assert.Empty(t, stored.CurrentTest)
+}
+
+// TestGroupRunner_SerialTest tests that a serial test waits for the tests in flight and runs
+// alone before later tests start
+func TestGroupRunner_SerialTest(t *testing.T) {
+	server := newLoadServer(t)
+	svc, db := newRunService(t, server.URL, 1)
+	createGroup(t, db, &models.TestGroup{GroupID: "billing"}, "/a?delay=120", "/b?delay=60")
+	serial := httpTest("billing", "/migrate?delay=50")
+	serial.Serial = true
+	require.NoError(t, db.Create(serial).Error)
+	require.NoError(t, db.Create(httpTest("billing", "/c?delay=10")).Error)
+	require.NoError(t, db.Create(httpTest("billing", "/d?delay=10")).Error)
+
+	run, err := svc.ExecuteTestGroup(context.Background(), "billing", GroupRunOptions{Concurrency: 4})
+	require.NoError(t, err)
+
+	migrateStart, migrateEnd := server.index("/migrate", true), server.index("/migrate", false)
+	require.NotEqual(t, -1, migrateStart)
+	for _, earlier := range []string{"/a", "/b"} {
+		assert.Less(t, server.index(earlier, false), migrateStart, "%s should finish before the serial test starts", earlier)
+	}
+	for _, later := range []string{"/c", "/d"} {
+		assert.Greater(t, server.index(later, true), migrateEnd, "%s should start after the serial test finishes", later)
+	}
+
+	// The tests before the serial test ran together
+	assert.Less(t, server.index("/b", true), server.index("/a", false))
+
+	assert.Equal(t, []string{"billing-a", "billing-b", "billing-migrate", "billing-c", "billing-d"}, savedTestIDs(t, db, run.RunID))
+	assert.Equal(t, 5, run.Passed)
+	assert.Equal(t, 5, run.Completed)
+}
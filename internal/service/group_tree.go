@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"test-management-service/internal/models"
+	"test-management-service/internal/testcase"
+)
+
+// groupNode 分组运行中的一个分组，及其测试和（递归运行时）子分组
+type groupNode struct {
+	group    *models.TestGroup             // 分组记录不存在时为 nil
+	executor *testcase.UnifiedTestExecutor // 使用分组目标地址和 TLS 配置的执行器
+	tests    []models.TestCase
+	children []*groupNode
+	total    int // 含子分组的测试总数
+
+	summary *models.GroupRunSummary   // 递归运行时的分组统计
+	path    []*models.GroupRunSummary // 从运行分组到本分组的统计，测试结果计入其中每一项
+}
+
+// loadGroupTree loads the tests of a group and, for a recursive run, the subtrees of its
+// subgroups in creation order. Subgroups whose parents form a cycle are rejected.
+func (s *testService) loadGroupTree(groupID string, group *models.TestGroup, recursive bool) (*groupNode, error) {
+	return s.loadGroupNode(groupID, group, recursive && group != nil, nil, map[string]bool{})
+}
+
+// loadGroupNode loads a group's subtree; visiting holds the groups from the run's group to this one
+func (s *testService) loadGroupNode(groupID string, group *models.TestGroup, recursive bool, path []*models.GroupRunSummary, visiting map[string]bool) (*groupNode, error) {
+	tests, err := s.caseRepo.FindByGroupID(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tests in group %s: %w", groupID, err)
+	}
+	node := &groupNode{group: group, executor: s.executor, tests: tests, total: len(tests)}
+	if group != nil {
+		if node.executor, err = s.executorForGroup(group); err != nil {
+			return nil, err
+		}
+	}
+	if !recursive {
+		return node, nil
+	}
+
+	visiting[groupID] = true
+	defer delete(visiting, groupID)
+	node.summary = &models.GroupRunSummary{GroupID: groupID, Name: group.Name}
+	node.path = append(append([]*models.GroupRunSummary{}, path...), node.summary)
+
+	children, err := s.groupRepo.FindByParentID(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find subgroups of group %s: %w", groupID, err)
+	}
+	for i := range children {
+		child := &children[i]
+		if visiting[child.GroupID] {
+			return nil, fmt.Errorf("test group cycle: %s", groupCycle(node.path, child.GroupID))
+		}
+		childNode, err := s.loadGroupNode(child.GroupID, child, true, node.path, visiting)
+		if err != nil {
+			return nil, err
+		}
+		node.children = append(node.children, childNode)
+		node.summary.Children = append(node.summary.Children, childNode.summary)
+		node.total += childNode.total
+	}
+	node.summary.Total = node.total
+	return node, nil
+}
+
+// loadAncestors returns the ancestors of a group with their executors, outermost first
+func (s *testService) loadAncestors(group *models.TestGroup) ([]*groupNode, error) {
+	levels := s.groupAncestry(group)
+	ancestors := make([]*groupNode, 0, len(levels)-1)
+	for _, level := range levels[:len(levels)-1] {
+		executor, err := s.executorForGroup(level)
+		if err != nil {
+			return nil, err
+		}
+		ancestors = append(ancestors, &groupNode{group: level, executor: executor})
+	}
+	return ancestors, nil
+}
+
+// groupCycle describes the groups of a cycle, from the repeated group back to itself
+func groupCycle(path []*models.GroupRunSummary, groupID string) string {
+	var ids []string
+	for _, summary := range path {
+		if summary.GroupID == groupID || len(ids) > 0 {
+			ids = append(ids, summary.GroupID)
+		}
+	}
+	return strings.Join(append(ids, groupID), " -> ")
+}
+
+// executeGroupNode runs a group's setup hooks, its tests, the subtrees of its subgroups and its
+// teardown hooks, using the group's target host and TLS settings. The values saved by a subgroup's
+// hooks and tests are visible only within its subtree. A failed setup skips the group's subtree.
+// It returns the hook errors of the subtree.
+func (s *testService) executeGroupNode(ctx context.Context, run *models.TestRun, node *groupNode, vars *testcase.RunVariables, concurrency int, skip bool) []string {
+	executor := node.executor
+
+	var errs, own []string
+	setUp := node.group != nil && !skip
+	if setUp {
+		if err := executor.ExecuteGroupSetupHooks(ctx, node.group.GroupID, convertHooks(node.group.SetupHooks), vars); err != nil {
+			own = append(own, err.Error())
+			errs = append(errs, err.Error())
+			skip = true
+		}
+	}
+
+	// Tests not started before the run is interrupted or after a failed setup are skipped
+	newGroupRunner(s, executor, run, node.path, len(node.tests)).execute(ctx, node.tests, concurrency, skip)
+
+	for _, child := range node.children {
+		childVars := testcase.NewRunVariables()
+		for name, value := range vars.Values() {
+			childVars.Set(name, value)
+		}
+		childCtx := testcase.WithRunVariables(ctx, childVars)
+		errs = append(errs, s.executeGroupNode(childCtx, run, child, childVars, concurrency, skip)...)
+	}
+
+	// Teardown hooks run even when setup failed
+	if setUp {
+		if err := executor.ExecuteGroupTeardownHooks(ctx, node.group.GroupID, convertHooks(node.group.TeardownHooks), vars); err != nil {
+			own = append(own, err.Error())
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if node.summary != nil {
+		node.summary.Error = strings.Join(own, "; ")
+	}
+	return errs
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"test-management-service/internal/models"
+)
+
+// newTreeServer answers every request with {"token": <last path segment>}, or a 500 for paths
+// starting with /fail, and returns the request URIs received so far
+func newTreeServer(t *testing.T) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.URL.RequestURI())
+		mu.Unlock()
+		if strings.HasPrefix(r.URL.Path, "/fail") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"token": path.Base(r.URL.Path)})
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requests...)
+	}
+}
+
+// treeHook returns an HTTP group hook requesting path, optionally saving the response token
+func treeHook(path string, saveToken bool) models.JSONArray {
+	hook := map[string]interface{}{
+		"type": "http",
+		"name": path,
+		"http": map[string]interface{}{"method": "POST", "path": path},
+	}
+	if saveToken {
+		hook["extract"] = map[string]interface{}{"token": "$.body.token"}
+	}
+	return models.JSONArray{hook}
+}
+
+// TestRecursiveRun_HooksAndVariables tests that each subgroup's hooks run once around its
+// subtree, that values saved in a subtree are not visible to its siblings or parent, and that
+// the run summary counts each group's subtree
+func TestRecursiveRun_HooksAndVariables(t *testing.T) {
+	server, requests := newTreeServer(t)
+	svc, db := newRunService(t, server.URL, 1)
+
+	groups := []*models.TestGroup{
+		{GroupID: "root", SetupHooks: treeHook("/setup/root", true), TeardownHooks: treeHook("/teardown/root?token={{token}}", false)},
+		{GroupID: "alpha", ParentID: "root", SetupHooks: treeHook("/setup/alpha", true), TeardownHooks: treeHook("/teardown/alpha", false)},
+		{GroupID: "alpha1", ParentID: "alpha", SetupHooks: treeHook("/setup/alpha1", false), TeardownHooks: treeHook("/teardown/alpha1", false)},
+		{GroupID: "beta", ParentID: "root", SetupHooks: treeHook("/setup/beta", false), TeardownHooks: treeHook("/teardown/beta", false)},
+	}
+	tests := map[string][]string{
+		"root":   {"/check/root?token={{token}}"},
+		"alpha":  {"/check/alpha?token={{token}}"},
+		"alpha1": {"/check/alpha1?token={{token}}", "/fail/alpha1"},
+		"beta":   {"/check/beta?token={{token}}"},
+	}
+	for _, group := range groups {
+		createGroup(t, db, group, tests[group.GroupID]...)
+	}
+
+	run, err := svc.ExecuteTestGroup(context.Background(), "root", GroupRunOptions{Recursive: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"/setup/root",
+		"/check/root?token=root",
+		"/setup/alpha",
+		"/check/alpha?token=alpha",
+		"/setup/alpha1",
+		"/check/alpha1?token=alpha",
+		"/fail/alpha1",
+		"/teardown/alpha1",
+		"/teardown/alpha",
+		"/setup/beta",
+		"/check/beta?token=root",
+		"/teardown/beta",
+		"/teardown/root?token=root",
+	}, requests())
+
+	stored, err := svc.GetTestRun(run.RunID)
+	require.NoError(t, err)
+	assert.Equal(t, "completed", stored.Status)
+	assert.Empty(t, stored.Error)
+	assert.Equal(t, 5, stored.Total)
+	assert.Equal(t, 4, stored.Passed)
+	assert.Equal(t, 1, stored.Failed)
+
+	require.NotNil(t, stored.Summary)
+	root := stored.Summary
+	assert.Equal(t, models.GroupRunSummary{GroupID: "root", Name: "root", Total: 5, Passed: 4, Failed: 1}, summaryCounts(root))
+	require.Len(t, root.Children, 2)
+	alpha, beta := root.Children[0], root.Children[1]
+	assert.Equal(t, models.GroupRunSummary{GroupID: "alpha", Name: "alpha", Total: 3, Passed: 2, Failed: 1}, summaryCounts(alpha))
+	assert.Equal(t, models.GroupRunSummary{GroupID: "beta", Name: "beta", Total: 1, Passed: 1}, summaryCounts(beta))
+	require.Len(t, alpha.Children, 1)
+	assert.Equal(t, models.GroupRunSummary{GroupID: "alpha1", Name: "alpha1", Total: 2, Passed: 1, Failed: 1}, summaryCounts(alpha.Children[0]))
+	assert.Empty(t, beta.Children)
+
+	// Without the recursive option only the group's own tests run
+	run, err = svc.ExecuteTestGroup(context.Background(), "root", GroupRunOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Total)
+	assert.Nil(t, run.Summary)
+}
+
+// summaryCounts returns a summary without its children
+func summaryCounts(summary *models.GroupRunSummary) models.GroupRunSummary {
+	counts := *summary
+	counts.Children = nil
+	return counts
+}
+
+// TestRecursiveRun_Cycle tests that a recursive run of groups whose parents form a cycle is rejected
+func TestRecursiveRun_Cycle(t *testing.T) {
+	server, requests := newTreeServer(t)
+	svc, db := newRunService(t, server.URL, 1)
+	createGroup(t, db, &models.TestGroup{GroupID: "a", ParentID: "c"}, "/check/a")
+	createGroup(t, db, &models.TestGroup{GroupID: "b", ParentID: "a"}, "/check/b")
+	createGroup(t, db, &models.TestGroup{GroupID: "c", ParentID: "b"}, "/check/c")
+
+	_, err := svc.QueueTestGroup("a", GroupRunOptions{Recursive: true})
+	assert.EqualError(t, err, "test group cycle: a -> b -> c -> a")
+
+	_, err = svc.ExecuteTestGroup(context.Background(), "b", GroupRunOptions{Recursive: true})
+	assert.EqualError(t, err, "test group cycle: b -> c -> a -> b")
+	assert.Empty(t, requests())
+
+	var runs []models.TestRun
+	require.NoError(t, db.Find(&runs).Error)
+	require.Len(t, runs, 1)
+	assert.Equal(t, "error", runs[0].Status)
+	assert.Equal(t, "test group cycle: b -> c -> a -> b", runs[0].Error)
+}
+
+// TestRecursiveRun_DeepTree tests that a deeply nested tree runs every level's tests and counts
+// them in each ancestor's summary
+func TestRecursiveRun_DeepTree(t *testing.T) {
+	const depth = 50
+	server, requests := newTreeServer(t)
+	svc, db := newRunService(t, server.URL, 1)
+	for i := 0; i < depth; i++ {
+		group := &models.TestGroup{GroupID: fmt.Sprintf("level%d", i)}
+		if i > 0 {
+			group.ParentID = fmt.Sprintf("level%d", i-1)
+		}
+		createGroup(t, db, group, fmt.Sprintf("/check/level%d", i))
+	}
+
+	run, err := svc.ExecuteTestGroup(context.Background(), "level0", GroupRunOptions{Recursive: true})
+	require.NoError(t, err)
+	assert.Equal(t, depth, run.Total)
+	assert.Equal(t, depth, run.Passed)
+	assert.Len(t, requests(), depth)
+
+	summary := run.Summary
+	for i := 0; i < depth; i++ {
+		require.NotNil(t, summary)
+		assert.Equal(t, fmt.Sprintf("level%d", i), summary.GroupID)
+		assert.Equal(t, depth-i, summary.Total)
+		assert.Equal(t, depth-i, summary.Passed)
+		if i == depth-1 {
+			assert.Empty(t, summary.Children)
+			break
+		}
+		require.Len(t, summary.Children, 1)
+		summary = summary.Children[0]
+	}
+}
+
+// TestGroupRun_InvalidTLSConfig tests that a group whose TLS config cannot be read fails the run
+// instead of running with the default TLS settings
+func TestGroupRun_InvalidTLSConfig(t *testing.T) {
+	server, requests := newTreeServer(t)
+	svc, db := newRunService(t, server.URL, 1)
+	invalid := models.JSONB{"caCert": "/etc/ca.pem", "insecureSkipVerify": "no"}
+	createGroup(t, db, &models.TestGroup{GroupID: "secure", TLSConfig: invalid}, "/check/secure")
+	createGroup(t, db, &models.TestGroup{GroupID: "parent"}, "/check/parent")
+	createGroup(t, db, &models.TestGroup{GroupID: "child", ParentID: "parent", TLSConfig: invalid}, "/check/child")
+	createGroup(t, db, &models.TestGroup{GroupID: "grandchild", ParentID: "child"}, "/check/grandchild")
+
+	const message = "invalid tlsConfig in test group %s: json: cannot unmarshal string into Go struct field TLSConfig.insecureSkipVerify of type bool"
+
+	_, err := svc.ExecuteTestGroup(context.Background(), "secure", GroupRunOptions{})
+	assert.EqualError(t, err, fmt.Sprintf(message, "secure"))
+
+	// A subgroup's config is checked when a recursive run is queued
+	_, err = svc.QueueTestGroup("parent", GroupRunOptions{Recursive: true})
+	assert.EqualError(t, err, fmt.Sprintf(message, "child"))
+
+	// An ancestor's config applies to its hooks in a run of a descendant
+	_, err = svc.ExecuteTestGroup(context.Background(), "grandchild", GroupRunOptions{})
+	assert.EqualError(t, err, fmt.Sprintf(message, "child"))
+
+	_, err = svc.ExecuteTest(context.Background(), "secure-check/secure")
+	assert.EqualError(t, err, fmt.Sprintf(message, "secure"))
+	assert.Empty(t, requests())
+
+	var runs []models.TestRun
+	require.NoError(t, db.Order("id").Find(&runs).Error)
+	require.Len(t, runs, 2)
+	for _, run := range runs {
+		assert.Equal(t, "error", run.Status)
+		assert.Contains(t, run.Error, "invalid tlsConfig in test group")
+	}
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"test-management-service/internal/models"
+)
+
+// ErrRunNotActive 要取消的运行不在排队或执行中
+var ErrRunNotActive = errors.New("run is not in progress")
+
+// ErrGroupNotFound 要执行的测试分组不存在
+var ErrGroupNotFound = errors.New("test group not found")
+
+// groupRunJob 排队等待执行的分组运行
+type groupRunJob struct {
+	run     *models.TestRun
+	options GroupRunOptions
+}
+
+// activeRun 执行中的运行，可被取消
+type activeRun struct {
+	cancel context.CancelFunc
+	done   chan struct{} // 运行结果保存后关闭
+}
+
+// runQueue 分组运行队列，工作协程按提交顺序取出运行执行；同时跟踪执行中的运行以便取消
+type runQueue struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending []groupRunJob
+	running map[string]*activeRun
+	closed  bool
+}
+
+func newRunQueue() *runQueue {
+	q := &runQueue{running: make(map[string]*activeRun)}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// push adds a job to the end of the queue
+func (q *runQueue) push(job groupRunJob) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending = append(q.pending, job)
+	q.cond.Signal()
+}
+
+// pop waits for the next job and starts tracking it, returning the context to run it under;
+// it returns false once the queue is closed
+func (q *runQueue) pop(ctx context.Context) (groupRunJob, context.Context, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.pending) == 0 && !q.closed {
+		q.cond.Wait()
+	}
+	if q.closed {
+		return groupRunJob{}, nil, false
+	}
+	job := q.pending[0]
+	q.pending = q.pending[1:]
+	return job, q.startLocked(ctx, job.run.RunID), true
+}
+
+// start tracks a run executed outside the queue and returns the context to run it under
+func (q *runQueue) start(ctx context.Context, runID string) context.Context {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.startLocked(ctx, runID)
+}
+
+func (q *runQueue) startLocked(ctx context.Context, runID string) context.Context {
+	ctx, cancel := context.WithCancel(ctx)
+	q.running[runID] = &activeRun{cancel: cancel, done: make(chan struct{})}
+	return ctx
+}
+
+// finish stops tracking a run once its outcome is saved
+func (q *runQueue) finish(runID string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if run, ok := q.running[runID]; ok {
+		delete(q.running, runID)
+		run.cancel()
+		close(run.done)
+	}
+}
+
+// cancel removes a queued run and returns its job, or cancels a running run and returns a
+// channel closed once its outcome is saved. It returns false if the run is neither.
+func (q *runQueue) cancel(runID string) (*groupRunJob, <-chan struct{}, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, job := range q.pending {
+		if job.run.RunID == runID {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			return &job, nil, true
+		}
+	}
+	if run, ok := q.running[runID]; ok {
+		run.cancel()
+		return nil, run.done, true
+	}
+	return nil, nil, false
+}
+
+// close stops the workers; runs still queued are left for RecoverInterruptedRuns
+func (q *runQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.cond.Broadcast()
+}
+
+// QueueTestGroup creates a queued run of a group and returns it without waiting for it to execute.
+// Its progress and results are available from GetTestRun while a run worker executes it.
+func (s *testService) QueueTestGroup(groupID string, options GroupRunOptions) (*models.TestRun, error) {
+	group, err := s.groupRepo.FindByID(groupID)
+	if err != nil || group == nil {
+		return nil, fmt.Errorf("%w: %s", ErrGroupNotFound, groupID)
+	}
+	root, err := s.loadGroupTree(groupID, group, options.Recursive)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &models.TestRun{
+		RunID:   newRunID(),
+		Name:    group.Name,
+		GroupID: groupID,
+		Total:   root.total,
+		Summary: root.summary,
+		Status:  "queued",
+	}
+	if err := s.runRepo.Create(run); err != nil {
+		return nil, fmt.Errorf("failed to create test run: %w", err)
+	}
+
+	// The worker updates its own copy, so the caller can read the returned run while it executes
+	job := *run
+	s.queue.push(groupRunJob{run: &job, options: options})
+	return run, nil
+}
+
+// StartRunWorkers starts the workers that execute queued runs. They stop when ctx is done,
+// leaving the runs they are executing to be interrupted by ctx.
+func (s *testService) StartRunWorkers(ctx context.Context, workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		go s.runWorker(ctx)
+	}
+	go func() {
+		<-ctx.Done()
+		s.queue.close()
+	}()
+}
+
+// runWorker executes queued runs one at a time
+func (s *testService) runWorker(ctx context.Context) {
+	for {
+		job, runCtx, ok := s.queue.pop(ctx)
+		if !ok {
+			return
+		}
+		if err := s.executeGroupRun(runCtx, job.run, job.options); err != nil {
+			fmt.Printf("failed to execute test run %s: %v\n", job.run.RunID, err)
+		}
+		s.queue.finish(job.run.RunID)
+	}
+}
+
+// CancelTestRun cancels a queued or running test run and returns it once its outcome is saved.
+// A running run starts no further tests, interrupts the tests in progress and still runs its
+// teardown hooks; the results saved so far are kept.
+func (s *testService) CancelTestRun(runID string) (*models.TestRun, error) {
+	run, err := s.runRepo.FindByID(runID)
+	if err != nil {
+		return nil, fmt.Errorf("test run not found: %s", runID)
+	}
+
+	job, done, ok := s.queue.cancel(runID)
+	if !ok {
+		return nil, fmt.Errorf("%w: test run %s is %s", ErrRunNotActive, runID, run.Status)
+	}
+	if done != nil {
+		<-done
+		return s.runRepo.FindByID(runID)
+	}
+
+	// The run never started, so all its tests are skipped
+	run = job.run
+	run.Status = "cancelled"
+	run.Skipped = run.Total
+	run.Completed = run.Total
+	run.EndTime = time.Now()
+	if err := s.runRepo.Update(run); err != nil {
+		return nil, fmt.Errorf("failed to update test run: %w", err)
+	}
+	s.broadcastCancelled(run)
+	return run, nil
+}
+
+// broadcastCancelled notifies the run's websocket subscribers that it was cancelled
+func (s *testService) broadcastCancelled(run *models.TestRun) {
+	if s.hub != nil {
+		s.hub.Broadcast(run.RunID, "run_cancelled", run)
+	}
+}
+
+// RecoverInterruptedRuns marks runs left queued or running by a previous server process as interrupted.
+// It returns the number of runs marked and must be called before StartRunWorkers.
+func (s *testService) RecoverInterruptedRuns() (int, error) {
+	runs, err := s.runRepo.FindByStatus("queued", "running")
+	if err != nil {
+		return 0, fmt.Errorf("failed to find unfinished test runs: %w", err)
+	}
+
+	now := time.Now()
+	for i := range runs {
+		run := &runs[i]
+		run.Status = "interrupted"
+		run.CurrentTest = ""
+		run.EndTime = now
+		if !run.StartTime.IsZero() {
+			run.Duration = int(now.Sub(run.StartTime).Milliseconds())
+		}
+		if run.Error == "" {
+			run.Error = "the server stopped before the run finished"
+		}
+		if err := s.runRepo.Update(run); err != nil {
+			return i, fmt.Errorf("failed to update test run %s: %w", run.RunID, err)
+		}
+	}
+	return len(runs), nil
+}
+
+// newRunID returns a unique ID for a test run
+func newRunID() string {
+	return fmt.Sprintf("run-%d", time.Now().UnixNano())
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"test-management-service/internal/models"
+)
+
+// waitForStatus polls a run until it has the given status
+func waitForStatus(t *testing.T, svc *testService, runID, status string) *models.TestRun {
+	t.Helper()
+	var run *models.TestRun
+	require.Eventually(t, func() bool {
+		var err error
+		run, err = svc.GetTestRun(runID)
+		return err == nil && run.Status == status
+	}, 5*time.Second, 10*time.Millisecond, "run %s did not become %s", runID, status)
+	return run
+}
+
+// TestRunQueue_WorkerExecutesQueuedRun tests that a queued run is picked up by a worker and its
+// outcome saved
+func TestRunQueue_WorkerExecutesQueuedRun(t *testing.T) {
+	server := newLoadServer(t)
+	svc, db := newRunService(t, server.URL, 2)
+	createGroup(t, db, &models.TestGroup{GroupID: "checkout"}, "/cart?delay=20", "/pay?delay=20&status=500", "/ship?delay=20")
+
+	// Runs are queued until a worker starts
+	run, err := svc.QueueTestGroup("checkout", GroupRunOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "queued", run.Status)
+	assert.Equal(t, 3, run.Total)
+	assert.Equal(t, "checkout", run.GroupID)
+
+	stored, err := svc.GetTestRun(run.RunID)
+	require.NoError(t, err)
+	assert.Equal(t, "queued", stored.Status)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	svc.StartRunWorkers(ctx, 1)
+
+	stored = waitForStatus(t, svc, run.RunID, "completed")
+	assert.Equal(t, 3, stored.Completed)
+	assert.Equal(t, 2, stored.Passed)
+	assert.Equal(t, 1, stored.Failed)
+	assert.False(t, stored.StartTime.IsZero())
+	assert.False(t, stored.EndTime.IsZero())
+	assert.Equal(t, []string{"checkout-cart", "checkout-pay", "checkout-ship"}, savedTestIDs(t, db, run.RunID))
+
+	_, err = svc.QueueTestGroup("missing", GroupRunOptions{})
+	assert.ErrorIs(t, err, ErrGroupNotFound)
+	assert.EqualError(t, err, "test group not found: missing")
+}
+
+// TestRunQueue_CloseWhileIdle tests that idle workers stop when the queue is closed and that
+// runs queued afterwards stay queued
+func TestRunQueue_CloseWhileIdle(t *testing.T) {
+	queue := newRunQueue()
+	stopped := make(chan bool)
+	go func() {
+		_, _, ok := queue.pop(context.Background())
+		stopped <- ok
+	}()
+
+	queue.close()
+	select {
+	case ok := <-stopped:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("pop did not return after the queue was closed")
+	}
+
+	server := newLoadServer(t)
+	svc, db := newRunService(t, server.URL, 1)
+	createGroup(t, db, &models.TestGroup{GroupID: "idle"}, "/ping")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	svc.StartRunWorkers(ctx, 2)
+	cancel()
+	require.Eventually(t, func() bool {
+		svc.queue.mu.Lock()
+		defer svc.queue.mu.Unlock()
+		return svc.queue.closed
+	}, 5*time.Second, 10*time.Millisecond)
+
+	run, err := svc.QueueTestGroup("idle", GroupRunOptions{})
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+	stored, err := svc.GetTestRun(run.RunID)
+	require.NoError(t, err)
+	assert.Equal(t, "queued", stored.Status)
+	assert.Equal(t, -1, server.index("/ping", true))
+}
+
+// TestRecoverInterruptedRuns tests that runs left queued or running are marked interrupted and
+// finished runs are left alone
+func TestRecoverInterruptedRuns(t *testing.T) {
+	svc, db := newRunService(t, "http://localhost", 1)
+	started := time.Now().Add(-time.Minute)
+	runs := []*models.TestRun{
+		{RunID: "run-running", GroupID: "g", Status: "running", StartTime: started, Total: 4, Completed: 2, CurrentTest: "g-3"},
+		{RunID: "run-queued", GroupID: "g", Status: "queued", Total: 4},
+		{RunID: "run-done", GroupID: "g", Status: "completed", StartTime: started, EndTime: started.Add(time.Second), Total: 4, Completed: 4},
+	}
+	for _, run := range runs {
+		require.NoError(t, db.Create(run).Error)
+	}
+
+	count, err := svc.RecoverInterruptedRuns()
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	running, err := svc.GetTestRun("run-running")
+	require.NoError(t, err)
+	assert.Equal(t, "interrupted", running.Status)
+	assert.Equal(t, "the server stopped before the run finished", running.Error)
+	assert.Empty(t, running.CurrentTest)
+	assert.Equal(t, 2, running.Completed)
+	assert.GreaterOrEqual(t, running.Duration, int(time.Minute.Milliseconds()))
+
+	queued, err := svc.GetTestRun("run-queued")
+	require.NoError(t, err)
+	assert.Equal(t, "interrupted", queued.Status)
+	assert.Zero(t, queued.Duration)
+	assert.False(t, queued.EndTime.IsZero())
+
+	done, err := svc.GetTestRun("run-done")
+	require.NoError(t, err)
+	assert.Equal(t, "completed", done.Status)
+	assert.Empty(t, done.Error)
+
+	// Nothing is left to recover
+	count, err = svc.RecoverInterruptedRuns()
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+// TestCancelTestRun_Queued tests that cancelling a queued run skips all its tests and that no
+// worker executes it afterwards
+func TestCancelTestRun_Queued(t *testing.T) {
+	server := newLoadServer(t)
+	svc, db := newRunService(t, server.URL, 1)
+	createGroup(t, db, &models.TestGroup{GroupID: "reports"}, "/daily", "/weekly")
+
+	run, err := svc.QueueTestGroup("reports", GroupRunOptions{})
+	require.NoError(t, err)
+
+	cancelled, err := svc.CancelTestRun(run.RunID)
+	require.NoError(t, err)
+	assert.Equal(t, "cancelled", cancelled.Status)
+	assert.Equal(t, 2, cancelled.Skipped)
+	assert.Equal(t, 2, cancelled.Completed)
+	assert.False(t, cancelled.EndTime.IsZero())
+
+	stored, err := svc.GetTestRun(run.RunID)
+	require.NoError(t, err)
+	assert.Equal(t, "cancelled", stored.Status)
+	assert.Equal(t, 2, stored.Skipped)
+
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	svc.StartRunWorkers(ctx, 1)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, -1, server.index("/daily", true))
+	assert.Empty(t, savedTestIDs(t, db, run.RunID))
+}
+
+// TestCancelTestRun_Running tests that cancelling a running run interrupts the test in progress,
+// skips the rest, runs the group's teardown hooks and keeps the results saved so far
+func TestCancelTestRun_Running(t *testing.T) {
+	server := newLoadServer(t)
+	svc, db := newRunService(t, server.URL, 1)
+	createGroup(t, db, &models.TestGroup{
+		GroupID: "nightly",
+		TeardownHooks: models.JSONArray{map[string]interface{}{
+			"type": "http",
+			"name": "cleanup",
+			"http": map[string]interface{}{"method": "POST", "path": "/cleanup"},
+		}},
+	}, "/quick", "/slow?delay=5000", "/never")
+
+	run, err := svc.QueueTestGroup("nightly", GroupRunOptions{})
+	require.NoError(t, err)
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	svc.StartRunWorkers(ctx, 1)
+
+	require.Eventually(t, func() bool { return server.index("/slow", true) != -1 }, 5*time.Second, 10*time.Millisecond)
+	started := time.Now()
+	cancelled, err := svc.CancelTestRun(run.RunID)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(started), 3*time.Second, "the test in progress should be interrupted")
+
+	assert.Equal(t, "cancelled", cancelled.Status)
+	assert.Equal(t, 3, cancelled.Completed)
+	assert.Equal(t, 1, cancelled.Passed)
+	assert.Equal(t, 3, cancelled.Passed+cancelled.Failed+cancelled.Errors+cancelled.Skipped)
+	assert.Empty(t, cancelled.CurrentTest)
+	assert.False(t, cancelled.EndTime.IsZero())
+
+	assert.Equal(t, []string{"nightly-quick", "nightly-slow"}, savedTestIDs(t, db, run.RunID))
+	assert.Equal(t, -1, server.index("/never", true))
+	assert.NotEqual(t, -1, server.index("/cleanup", true), "teardown hooks should run after a cancel")
+}
+
+// TestCancelTestRun_NotActive tests that finished and unknown runs cannot be cancelled
+func TestCancelTestRun_NotActive(t *testing.T) {
+	svc, db := newRunService(t, "http://localhost", 1)
+	require.NoError(t, db.Create(&models.TestRun{RunID: "run-done", GroupID: "g", Status: "completed"}).Error)
+
+	_, err := svc.CancelTestRun("run-done")
+	assert.ErrorIs(t, err, ErrRunNotActive)
+	assert.EqualError(t, err, "run is not in progress: test run run-done is completed")
+
+	_, err = svc.CancelTestRun("run-missing")
+	assert.EqualError(t, err, "test run not found: run-missing")
+	assert.NotErrorIs(t, err, ErrRunNotActive)
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+
+	"test-management-service/internal/models"
+	"test-management-service/internal/repository"
+	"test-management-service/internal/testcase"
+)
+
+// SchemaService JSON Schema 资源管理服务接口
+type SchemaService interface {
+	CreateSchema(req *CreateSchemaRequest) (*models.JSONSchema, error)
+	UpdateSchema(schemaID string, req *UpdateSchemaRequest) (*models.JSONSchema, error)
+	DeleteSchema(schemaID string) error
+	GetSchema(schemaID string) (*models.JSONSchema, error)
+	ListSchemas(limit, offset int) ([]models.JSONSchema, int64, error)
+}
+
+type schemaService struct {
+	schemaRepo repository.JSONSchemaRepository
+}
+
+// NewSchemaService 创建 JSON Schema 资源管理服务
+func NewSchemaService(schemaRepo repository.JSONSchemaRepository) SchemaService {
+	return &schemaService{
+		schemaRepo: schemaRepo,
+	}
+}
+
+// ===== Request/Response DTOs =====
+
+type CreateSchemaRequest struct {
+	SchemaID    string                 `json:"schemaId" binding:"required"`
+	Name        string                 `json:"name" binding:"required"`
+	Description string                 `json:"description"`
+	Schema      map[string]interface{} `json:"schema" binding:"required"`
+}
+
+type UpdateSchemaRequest struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Schema      map[string]interface{} `json:"schema"`
+}
+
+// ===== Implementation =====
+
+func (s *schemaService) CreateSchema(req *CreateSchemaRequest) (*models.JSONSchema, error) {
+	// 检查 schemaId 是否已存在
+	existing, _ := s.schemaRepo.FindByID(req.SchemaID)
+	if existing != nil {
+		return nil, fmt.Errorf("schema with schemaId '%s' already exists", req.SchemaID)
+	}
+
+	// 保存前先编译，拒绝无效的 schema
+	if _, err := testcase.CompileJSONSchema(req.Schema); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	schema := &models.JSONSchema{
+		SchemaID:    req.SchemaID,
+		Name:        req.Name,
+		Description: req.Description,
+		Schema:      models.JSONB(req.Schema),
+	}
+
+	if err := s.schemaRepo.Create(schema); err != nil {
+		return nil, fmt.Errorf("failed to create schema: %w", err)
+	}
+
+	return schema, nil
+}
+
+func (s *schemaService) UpdateSchema(schemaID string, req *UpdateSchemaRequest) (*models.JSONSchema, error) {
+	schema, err := s.schemaRepo.FindByID(schemaID)
+	if err != nil || schema == nil {
+		return nil, fmt.Errorf("schema not found: %s", schemaID)
+	}
+
+	if req.Name != "" {
+		schema.Name = req.Name
+	}
+	if req.Description != "" {
+		schema.Description = req.Description
+	}
+	if req.Schema != nil {
+		if _, err := testcase.CompileJSONSchema(req.Schema); err != nil {
+			return nil, fmt.Errorf("invalid schema: %w", err)
+		}
+		schema.Schema = models.JSONB(req.Schema)
+	}
+
+	if err := s.schemaRepo.Update(schema); err != nil {
+		return nil, fmt.Errorf("failed to update schema: %w", err)
+	}
+
+	return schema, nil
+}
+
+func (s *schemaService) DeleteSchema(schemaID string) error {
+	schema, err := s.schemaRepo.FindByID(schemaID)
+	if err != nil || schema == nil {
+		return fmt.Errorf("schema not found: %s", schemaID)
+	}
+
+	return s.schemaRepo.Delete(schemaID)
+}
+
+func (s *schemaService) GetSchema(schemaID string) (*models.JSONSchema, error) {
+	return s.schemaRepo.FindByID(schemaID)
+}
+
+func (s *schemaService) ListSchemas(limit, offset int) ([]models.JSONSchema, int64, error) {
+	return s.schemaRepo.FindAll(limit, offset)
+}
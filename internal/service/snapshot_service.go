@@ -0,0 +1,74 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"test-management-service/internal/models"
+	"test-management-service/internal/repository"
+)
+
+// SnapshotService 响应快照审核服务接口
+type SnapshotService interface {
+	ListSnapshots(testID string) ([]models.Snapshot, error)
+	ListPendingSnapshots(limit, offset int) ([]models.Snapshot, int64, error)
+	GetSnapshot(testID, name string) (*models.Snapshot, error)
+	AcceptSnapshot(testID, name string) (*models.Snapshot, error)
+	DeleteSnapshot(testID, name string) error
+}
+
+type snapshotService struct {
+	snapshotRepo repository.SnapshotRepository
+}
+
+// NewSnapshotService 创建响应快照审核服务
+func NewSnapshotService(snapshotRepo repository.SnapshotRepository) SnapshotService {
+	return &snapshotService{
+		snapshotRepo: snapshotRepo,
+	}
+}
+
+func (s *snapshotService) ListSnapshots(testID string) ([]models.Snapshot, error) {
+	return s.snapshotRepo.FindByTestID(testID)
+}
+
+func (s *snapshotService) ListPendingSnapshots(limit, offset int) ([]models.Snapshot, int64, error) {
+	return s.snapshotRepo.FindByStatus("pending", limit, offset)
+}
+
+func (s *snapshotService) GetSnapshot(testID, name string) (*models.Snapshot, error) {
+	return s.snapshotRepo.GetSnapshot(testID, name)
+}
+
+// AcceptSnapshot 将待审核的响应提升为新的基线
+func (s *snapshotService) AcceptSnapshot(testID, name string) (*models.Snapshot, error) {
+	snapshot, err := s.snapshotRepo.GetSnapshot(testID, name)
+	if err != nil || snapshot == nil {
+		return nil, fmt.Errorf("snapshot not found: %s/%s", testID, name)
+	}
+	if snapshot.Status != "pending" || snapshot.Pending.IsNull() {
+		return nil, fmt.Errorf("snapshot %s/%s has no pending changes", testID, name)
+	}
+
+	now := time.Now()
+	snapshot.Baseline = snapshot.Pending
+	snapshot.Pending = models.JSONValue{}
+	snapshot.Diff = nil
+	snapshot.Status = "accepted"
+	snapshot.AcceptedAt = &now
+
+	if err := s.snapshotRepo.SaveSnapshot(snapshot); err != nil {
+		return nil, fmt.Errorf("failed to accept snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+func (s *snapshotService) DeleteSnapshot(testID, name string) error {
+	snapshot, err := s.snapshotRepo.GetSnapshot(testID, name)
+	if err != nil || snapshot == nil {
+		return fmt.Errorf("snapshot not found: %s/%s", testID, name)
+	}
+
+	return s.snapshotRepo.Delete(testID, name)
+}
@@ -1,15 +1,22 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"test-management-service/internal/models"
 	"test-management-service/internal/repository"
 	"test-management-service/internal/testcase"
+	"test-management-service/internal/websocket"
 )
 
+// harArtifactName 测试结果中 HTTP 请求记录附件的名称
+const harArtifactName = "requests.har"
+
 // TestService 测试服务接口
 type TestService interface {
 	// Test Case operations
@@ -28,16 +35,23 @@ type TestService interface {
 	GetTestGroupTree() ([]models.TestGroup, error)
 
 	// Test execution
-	ExecuteTest(testID string) (*models.TestResult, error)
-	ExecuteTestGroup(groupID string) (*models.TestRun, error)
+	ExecuteTest(ctx context.Context, testID string) (*models.TestResult, error)
+	ExecuteTestGroup(ctx context.Context, groupID string, options GroupRunOptions) (*models.TestRun, error)
+	QueueTestGroup(groupID string, options GroupRunOptions) (*models.TestRun, error)
+
+	// Run workers
+	StartRunWorkers(ctx context.Context, workers int)
+	RecoverInterruptedRuns() (int, error)
 
 	// Test results
 	GetTestResult(id uint) (*models.TestResult, error)
+	GetTestResultArtifact(id uint, name string) (*models.TestArtifact, error)
 	GetTestHistory(testID string, limit int) ([]models.TestResult, error)
 
 	// Test runs
 	GetTestRun(runID string) (*models.TestRun, error)
 	ListTestRuns(limit, offset int) ([]models.TestRun, int64, error)
+	CancelTestRun(runID string) (*models.TestRun, error)
 }
 
 type testService struct {
@@ -46,6 +60,10 @@ type testService struct {
 	resultRepo repository.TestResultRepository
 	runRepo    repository.TestRunRepository
 	executor   *testcase.UnifiedTestExecutor
+	queue      *runQueue
+	hub        *websocket.Hub
+
+	defaultConcurrency int // 分组运行默认并发数
 }
 
 // NewTestService creates a new test service
@@ -55,13 +73,18 @@ func NewTestService(
 	resultRepo repository.TestResultRepository,
 	runRepo repository.TestRunRepository,
 	executor *testcase.UnifiedTestExecutor,
+	hub *websocket.Hub,
+	defaultConcurrency int,
 ) TestService {
 	return &testService{
-		caseRepo:   caseRepo,
-		groupRepo:  groupRepo,
-		resultRepo: resultRepo,
-		runRepo:    runRepo,
-		executor:   executor,
+		caseRepo:           caseRepo,
+		groupRepo:          groupRepo,
+		resultRepo:         resultRepo,
+		runRepo:            runRepo,
+		executor:           executor,
+		queue:              newRunQueue(),
+		hub:                hub,
+		defaultConcurrency: defaultConcurrency,
 	}
 }
 
@@ -84,11 +107,18 @@ type CreateTestCaseRequest struct {
 	// Existing fields
 	HTTP          map[string]interface{} `json:"http"`
 	Command       map[string]interface{} `json:"command"`
+	GRPC          map[string]interface{} `json:"grpc"`
+	WebSocket     map[string]interface{} `json:"websocket"`
+	Database      map[string]interface{} `json:"database"`
+	Performance   map[string]interface{} `json:"performance"`
+	Security      map[string]interface{} `json:"security"`
 	Integration   map[string]interface{} `json:"integration"`
 	Assertions    []interface{}          `json:"assertions"`
 	Tags          []interface{}          `json:"tags"`
 	SetupHooks    []interface{}          `json:"setupHooks"`
 	TeardownHooks []interface{}          `json:"teardownHooks"`
+	Extract       []interface{}          `json:"extract"` // 从响应提取变量：name/type/path/group
+	Serial        bool                   `json:"serial"`  // 分组并发运行时单独执行
 }
 
 type UpdateTestCaseRequest struct {
@@ -104,10 +134,23 @@ type UpdateTestCaseRequest struct {
 
 	HTTP          map[string]interface{} `json:"http"`
 	Command       map[string]interface{} `json:"command"`
+	GRPC          map[string]interface{} `json:"grpc"`
+	WebSocket     map[string]interface{} `json:"websocket"`
+	Database      map[string]interface{} `json:"database"`
+	Performance   map[string]interface{} `json:"performance"`
+	Security      map[string]interface{} `json:"security"`
 	Assertions    []interface{}          `json:"assertions"`
 	Tags          []interface{}          `json:"tags"`
 	SetupHooks    []interface{}          `json:"setupHooks"`
 	TeardownHooks []interface{}          `json:"teardownHooks"`
+	Extract       []interface{}          `json:"extract"` // 从响应提取变量：name/type/path/group
+	Serial        *bool                  `json:"serial"`  // 分组并发运行时单独执行，不传表示不修改
+}
+
+// GroupRunOptions 分组运行选项
+type GroupRunOptions struct {
+	Concurrency int  // 同时执行的测试数，0 表示使用配置的默认值
+	Recursive   bool // 同时执行所有子分组，结果按分组汇总到 TestRun.Summary
 }
 
 type CreateTestGroupRequest struct {
@@ -116,12 +159,26 @@ type CreateTestGroupRequest struct {
 	ParentID    string `json:"parentId"`
 	Description string `json:"description"`
 	TargetHost  string `json:"targetHost"` // 测试目标服务地址
+	Timeout     int    `json:"timeout"`    // 分组运行超时（秒），0 表示不限制
+
+	TLSConfig    map[string]interface{} `json:"tlsConfig"`    // TLS 客户端配置
+	ShareCookies bool                   `json:"shareCookies"` // 分组运行内的测试共享 Cookie 会话
+
+	SetupHooks    []interface{} `json:"setupHooks"`    // 分组运行开始前执行一次的钩子
+	TeardownHooks []interface{} `json:"teardownHooks"` // 分组运行结束后执行一次的钩子
 }
 
 type UpdateTestGroupRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	TargetHost  string `json:"targetHost"` // 测试目标服务地址
+	Timeout     *int   `json:"timeout"`    // 分组运行超时（秒），不传表示不修改
+
+	TLSConfig    map[string]interface{} `json:"tlsConfig"`    // TLS 客户端配置，不传表示不修改，传空对象表示清除
+	ShareCookies *bool                  `json:"shareCookies"` // 分组运行内的测试共享 Cookie 会话，不传表示不修改
+
+	SetupHooks    []interface{} `json:"setupHooks"`    // 分组级前置钩子，不传表示不修改
+	TeardownHooks []interface{} `json:"teardownHooks"` // 分组级后置钩子，不传表示不修改
 }
 
 // ===== Test Case Operations =====
@@ -154,6 +211,21 @@ func (s *testService) CreateTestCase(req *CreateTestCaseRequest) (*models.TestCa
 	if req.Command != nil {
 		tc.CommandConfig = req.Command
 	}
+	if req.GRPC != nil {
+		tc.GRPCConfig = req.GRPC
+	}
+	if req.WebSocket != nil {
+		tc.WebSocketConfig = req.WebSocket
+	}
+	if req.Database != nil {
+		tc.DatabaseConfig = req.Database
+	}
+	if req.Performance != nil {
+		tc.PerformanceConfig = req.Performance
+	}
+	if req.Security != nil {
+		tc.SecurityConfig = req.Security
+	}
 	if req.Integration != nil {
 		tc.IntegrationConfig = req.Integration
 	}
@@ -169,6 +241,10 @@ func (s *testService) CreateTestCase(req *CreateTestCaseRequest) (*models.TestCa
 	if req.TeardownHooks != nil {
 		tc.TeardownHooks = req.TeardownHooks
 	}
+	if req.Extract != nil {
+		tc.Extract = req.Extract
+	}
+	tc.Serial = req.Serial
 
 	// Workflow integration
 	if req.WorkflowID != "" {
@@ -215,6 +291,21 @@ func (s *testService) UpdateTestCase(testID string, req *UpdateTestCaseRequest)
 	if req.Command != nil {
 		tc.CommandConfig = req.Command
 	}
+	if req.GRPC != nil {
+		tc.GRPCConfig = req.GRPC
+	}
+	if req.WebSocket != nil {
+		tc.WebSocketConfig = req.WebSocket
+	}
+	if req.Database != nil {
+		tc.DatabaseConfig = req.Database
+	}
+	if req.Performance != nil {
+		tc.PerformanceConfig = req.Performance
+	}
+	if req.Security != nil {
+		tc.SecurityConfig = req.Security
+	}
 	if req.Assertions != nil {
 		tc.Assertions = req.Assertions
 	}
@@ -227,6 +318,12 @@ func (s *testService) UpdateTestCase(testID string, req *UpdateTestCaseRequest)
 	if req.TeardownHooks != nil {
 		tc.TeardownHooks = req.TeardownHooks
 	}
+	if req.Extract != nil {
+		tc.Extract = req.Extract
+	}
+	if req.Serial != nil {
+		tc.Serial = *req.Serial
+	}
 
 	// Workflow integration
 	if req.WorkflowID != "" {
@@ -263,11 +360,20 @@ func (s *testService) SearchTestCases(query string) ([]models.TestCase, error) {
 
 func (s *testService) CreateTestGroup(req *CreateTestGroupRequest) (*models.TestGroup, error) {
 	group := &models.TestGroup{
-		GroupID:     req.GroupID,
-		Name:        req.Name,
-		ParentID:    req.ParentID,
-		Description: req.Description,
-		TargetHost:  req.TargetHost,
+		GroupID:      req.GroupID,
+		Name:         req.Name,
+		ParentID:     req.ParentID,
+		Description:  req.Description,
+		TargetHost:   req.TargetHost,
+		Timeout:      req.Timeout,
+		TLSConfig:    models.JSONB(req.TLSConfig),
+		ShareCookies: req.ShareCookies,
+	}
+	if req.SetupHooks != nil {
+		group.SetupHooks = req.SetupHooks
+	}
+	if req.TeardownHooks != nil {
+		group.TeardownHooks = req.TeardownHooks
 	}
 
 	if err := s.groupRepo.Create(group); err != nil {
@@ -294,6 +400,21 @@ func (s *testService) UpdateTestGroup(groupID string, req *UpdateTestGroupReques
 	}
 	// Allow clearing targetHost by setting to empty string
 	group.TargetHost = req.TargetHost
+	if req.Timeout != nil {
+		group.Timeout = *req.Timeout
+	}
+	if req.TLSConfig != nil {
+		group.TLSConfig = models.JSONB(req.TLSConfig)
+	}
+	if req.ShareCookies != nil {
+		group.ShareCookies = *req.ShareCookies
+	}
+	if req.SetupHooks != nil {
+		group.SetupHooks = req.SetupHooks
+	}
+	if req.TeardownHooks != nil {
+		group.TeardownHooks = req.TeardownHooks
+	}
 
 	if err := s.groupRepo.Update(group); err != nil {
 		return nil, fmt.Errorf("failed to update test group: %w", err)
@@ -316,7 +437,7 @@ func (s *testService) GetTestGroupTree() ([]models.TestGroup, error) {
 
 // ===== Test Execution =====
 
-func (s *testService) ExecuteTest(testID string) (*models.TestResult, error) {
+func (s *testService) ExecuteTest(ctx context.Context, testID string) (*models.TestResult, error) {
 	// Get test case from database
 	tc, err := s.caseRepo.FindByID(testID)
 	if err != nil {
@@ -326,13 +447,14 @@ func (s *testService) ExecuteTest(testID string) (*models.TestResult, error) {
 		return nil, fmt.Errorf("test case not found: %s", testID)
 	}
 
-	// Get the test group to check for custom target host
+	// Get the test group to check for custom target host and TLS settings
 	executor := s.executor
 	if tc.GroupID != "" {
 		group, err := s.groupRepo.FindByID(tc.GroupID)
-		if err == nil && group != nil && group.TargetHost != "" {
-			// Use group-specific target host
-			executor = testcase.NewExecutor(group.TargetHost)
+		if err == nil && group != nil {
+			if executor, err = s.executorForGroup(group); err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -340,7 +462,7 @@ func (s *testService) ExecuteTest(testID string) (*models.TestResult, error) {
 	execTC := s.convertToExecutorTestCase(tc)
 
 	// Execute test
-	result := executor.Execute(execTC)
+	result := executor.ExecuteContext(ctx, execTC)
 
 	// Convert result to model and save
 	dbResult := s.convertToModelResult(result)
@@ -351,68 +473,124 @@ func (s *testService) ExecuteTest(testID string) (*models.TestResult, error) {
 	return dbResult, nil
 }
 
-func (s *testService) ExecuteTestGroup(groupID string) (*models.TestRun, error) {
-	// Get all tests in group
-	tests, err := s.caseRepo.FindByGroupID(groupID)
+func (s *testService) ExecuteTestGroup(ctx context.Context, groupID string, options GroupRunOptions) (*models.TestRun, error) {
+	run := &models.TestRun{
+		RunID:   newRunID(),
+		GroupID: groupID,
+		Status:  "running",
+	}
+	if err := s.runRepo.Create(run); err != nil {
+		return nil, fmt.Errorf("failed to create test run: %w", err)
+	}
+
+	ctx = s.queue.start(ctx, run.RunID)
+	defer s.queue.finish(run.RunID)
+	if err := s.executeGroupRun(ctx, run, options); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// executeGroupRun executes the tests of a created run's group and saves the run's progress and outcome
+func (s *testService) executeGroupRun(ctx context.Context, run *models.TestRun, options GroupRunOptions) error {
+	// Get the test group to check for custom target host, TLS settings and hooks
+	group, err := s.groupRepo.FindByID(run.GroupID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find tests in group: %w", err)
+		group = nil
 	}
 
-	// Get the test group to check for custom target host
-	executor := s.executor
-	group, err := s.groupRepo.FindByID(groupID)
-	if err == nil && group != nil && group.TargetHost != "" {
-		// Use group-specific target host
-		executor = testcase.NewExecutor(group.TargetHost)
+	// Get all tests in group, and in its subgroups for a recursive run
+	root, err := s.loadGroupTree(run.GroupID, group, options.Recursive)
+	var ancestors []*groupNode
+	if err == nil && group != nil {
+		ancestors, err = s.loadAncestors(group)
+	}
+	if err != nil {
+		run.Status = "error"
+		run.Error = err.Error()
+		s.runRepo.Update(run)
+		return err
 	}
 
-	// Create test run
-	runID := fmt.Sprintf("run-%d", time.Now().Unix())
-	run := &models.TestRun{
-		RunID:     runID,
-		Total:     len(tests),
-		StartTime: time.Now(),
-		Status:    "running",
+	// Apply the group run timeout
+	if group != nil && group.Timeout > 0 {
+		timeout := time.Duration(group.Timeout) * time.Second
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, fmt.Errorf("group run timed out after %v", timeout))
+		defer cancel()
 	}
 
-	if err := s.runRepo.Create(run); err != nil {
-		return nil, fmt.Errorf("failed to create test run: %w", err)
+	// Carry one cookie session through the whole run, e.g. a login test followed by tests that need it
+	if group != nil && group.ShareCookies {
+		ctx = testcase.WithCookieJar(ctx, testcase.NewCookieJar())
 	}
 
-	// Execute each test
-	for _, tc := range tests {
-		execTC := s.convertToExecutorTestCase(&tc)
-		result := executor.Execute(execTC)
+	// Pass values saved by group hooks and extracted by each test to the tests that follow
+	vars := testcase.NewRunVariables()
+	ctx = testcase.WithRunVariables(ctx, vars)
 
-		dbResult := s.convertToModelResult(result)
-		dbResult.RunID = runID
+	// Start the run
+	run.Total = root.total
+	run.Summary = root.summary
+	run.StartTime = time.Now()
+	run.Status = "running"
+	if err := s.runRepo.Update(run); err != nil {
+		return fmt.Errorf("failed to update test run: %w", err)
+	}
 
-		if err := s.resultRepo.Create(dbResult); err != nil {
-			fmt.Printf("failed to save result for test %s: %v\n", tc.TestID, err)
-			continue
+	// Run the setup hooks of the group's ancestors, outermost first, once for the run
+	var runErrors []string
+	setUp := 0
+	for _, level := range ancestors {
+		setUp++
+		if err := level.executor.ExecuteGroupSetupHooks(ctx, level.group.GroupID, convertHooks(level.group.SetupHooks), vars); err != nil {
+			runErrors = append(runErrors, err.Error())
+			break
 		}
+	}
+
+	// Execute the group with its hooks, and its subgroups for a recursive run
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = s.defaultConcurrency
+	}
+	runErrors = append(runErrors, s.executeGroupNode(ctx, run, root, vars, concurrency, len(runErrors) > 0)...)
 
-		// Update run statistics
-		switch result.Status {
-		case "passed":
-			run.Passed++
-		case "failed":
-			run.Failed++
-		case "error":
-			run.Errors++
+	// Run the ancestors' teardown hooks innermost first, including those of a level whose setup failed
+	for i := setUp - 1; i >= 0; i-- {
+		level := ancestors[i]
+		if err := level.executor.ExecuteGroupTeardownHooks(ctx, level.group.GroupID, convertHooks(level.group.TeardownHooks), vars); err != nil {
+			runErrors = append(runErrors, err.Error())
 		}
 	}
+	run.Error = strings.Join(runErrors, "; ")
 
 	// Update run status
 	run.EndTime = time.Now()
 	run.Duration = int(run.EndTime.Sub(run.StartTime).Milliseconds())
-	run.Status = "completed"
+	run.Status = runStatus(ctx)
+	run.CurrentTest = ""
 
 	if err := s.runRepo.Update(run); err != nil {
-		return nil, fmt.Errorf("failed to update test run: %w", err)
+		return fmt.Errorf("failed to update test run: %w", err)
+	}
+	if run.Status == "cancelled" {
+		s.broadcastCancelled(run)
 	}
 
-	return run, nil
+	return nil
+}
+
+// runStatus returns the final status of a test run from its context
+func runStatus(ctx context.Context) string {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(ctx.Err(), context.Canceled):
+		return "cancelled"
+	default:
+		return "completed"
+	}
 }
 
 // ===== Test Results =====
@@ -421,6 +599,10 @@ func (s *testService) GetTestResult(id uint) (*models.TestResult, error) {
 	return s.resultRepo.FindByID(id)
 }
 
+func (s *testService) GetTestResultArtifact(id uint, name string) (*models.TestArtifact, error) {
+	return s.resultRepo.FindArtifact(id, name)
+}
+
 func (s *testService) GetTestHistory(testID string, limit int) ([]models.TestResult, error) {
 	return s.resultRepo.FindByTestID(testID, limit)
 }
@@ -437,12 +619,50 @@ func (s *testService) ListTestRuns(limit, offset int) ([]models.TestRun, int64,
 
 // ===== Helper Methods =====
 
+// groupAncestry returns the group preceded by its ancestors, outermost first
+func (s *testService) groupAncestry(group *models.TestGroup) []*models.TestGroup {
+	levels := []*models.TestGroup{group}
+	seen := map[string]bool{group.GroupID: true}
+	for parentID := group.ParentID; parentID != "" && !seen[parentID]; {
+		parent, err := s.groupRepo.FindByID(parentID)
+		if err != nil || parent == nil {
+			break
+		}
+		seen[parentID] = true
+		levels = append([]*models.TestGroup{parent}, levels...)
+		parentID = parent.ParentID
+	}
+	return levels
+}
+
+// executorForGroup 返回使用分组目标地址和 TLS 配置的执行器；TLS 配置无效时返回错误，不回退到默认配置
+func (s *testService) executorForGroup(group *models.TestGroup) (*testcase.UnifiedTestExecutor, error) {
+	executor := s.executor
+	if group.TargetHost != "" {
+		// Use group-specific target host
+		executor = executor.WithBaseURL(group.TargetHost)
+	}
+	if len(group.TLSConfig) > 0 {
+		tlsConfig := &testcase.TLSConfig{}
+		data, err := json.Marshal(group.TLSConfig)
+		if err == nil {
+			err = json.Unmarshal(data, tlsConfig)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid tlsConfig in test group %s: %w", group.GroupID, err)
+		}
+		executor = executor.WithTLSConfig(tlsConfig)
+	}
+	return executor, nil
+}
+
 func (s *testService) convertToExecutorTestCase(tc *models.TestCase) *testcase.TestCase {
 	execTC := &testcase.TestCase{
 		ID:      tc.TestID,
 		Name:    tc.Name,
 		Type:    tc.Type,
 		GroupID: tc.GroupID,
+		Timeout: tc.Timeout,
 	}
 
 	// Workflow integration
@@ -471,6 +691,7 @@ func (s *testService) convertToExecutorTestCase(tc *models.TestCase) *testcase.T
 		if body, ok := tc.HTTPConfig["body"].(map[string]interface{}); ok {
 			execTC.HTTP.Body = body
 		}
+		convertHTTPOptions(tc.HTTPConfig, execTC.HTTP)
 	}
 
 	// Convert Command config
@@ -489,6 +710,42 @@ func (s *testService) convertToExecutorTestCase(tc *models.TestCase) *testcase.T
 		if timeout, ok := tc.CommandConfig["timeout"].(float64); ok {
 			execTC.Command.Timeout = int(timeout)
 		}
+		convertCommandOptions(tc.CommandConfig, execTC.Command)
+	}
+
+	// Convert gRPC config
+	if tc.GRPCConfig != nil {
+		execTC.GRPC = &testcase.GRPCTest{}
+		data, _ := json.Marshal(tc.GRPCConfig)
+		json.Unmarshal(data, execTC.GRPC)
+	}
+
+	// Convert WebSocket config
+	if tc.WebSocketConfig != nil {
+		execTC.WebSocket = &testcase.WebSocketTest{}
+		data, _ := json.Marshal(tc.WebSocketConfig)
+		json.Unmarshal(data, execTC.WebSocket)
+	}
+
+	// Convert Database config
+	if tc.DatabaseConfig != nil {
+		execTC.Database = &testcase.DatabaseTest{}
+		data, _ := json.Marshal(tc.DatabaseConfig)
+		json.Unmarshal(data, execTC.Database)
+	}
+
+	// Convert Performance config
+	if tc.PerformanceConfig != nil {
+		execTC.Performance = &testcase.PerformanceTest{}
+		data, _ := json.Marshal(tc.PerformanceConfig)
+		json.Unmarshal(data, execTC.Performance)
+	}
+
+	// Convert Security config
+	if tc.SecurityConfig != nil {
+		execTC.Security = &testcase.SecurityTest{}
+		data, _ := json.Marshal(tc.SecurityConfig)
+		json.Unmarshal(data, execTC.Security)
 	}
 
 	// Convert Assertions
@@ -508,141 +765,22 @@ func (s *testService) convertToExecutorTestCase(tc *models.TestCase) *testcase.T
 				if operator, ok := assertMap["operator"].(string); ok {
 					assertion.Operator = operator
 				}
-				execTC.Assertions = append(execTC.Assertions, assertion)
-			}
-		}
-	}
-
-	// Convert Setup Hooks
-	if tc.SetupHooks != nil {
-		for _, h := range tc.SetupHooks {
-			if hookMap, ok := h.(map[string]interface{}); ok {
-				hook := testcase.Hook{}
-				if hType, ok := hookMap["type"].(string); ok {
-					hook.Type = hType
-				}
-				if name, ok := hookMap["name"].(string); ok {
-					hook.Name = name
-				}
-				if saveResponse, ok := hookMap["saveResponse"].(string); ok {
-					hook.SaveResponse = saveResponse
-				}
-				if runOnFailure, ok := hookMap["runOnFailure"].(bool); ok {
-					hook.RunOnFailure = runOnFailure
-				}
-				if continueOnError, ok := hookMap["continueOnError"].(bool); ok {
-					hook.ContinueOnError = continueOnError
+				if soft, ok := assertMap["soft"].(bool); ok {
+					assertion.Soft = soft
 				}
-
-				// Convert HTTP config for hook
-				if httpConfig, ok := hookMap["http"].(map[string]interface{}); ok {
-					hook.HTTP = &testcase.HTTPTest{}
-					if method, ok := httpConfig["method"].(string); ok {
-						hook.HTTP.Method = method
-					}
-					if path, ok := httpConfig["path"].(string); ok {
-						hook.HTTP.Path = path
-					}
-					if headers, ok := httpConfig["headers"].(map[string]interface{}); ok {
-						hook.HTTP.Headers = make(map[string]string)
-						for k, v := range headers {
-							if str, ok := v.(string); ok {
-								hook.HTTP.Headers[k] = str
-							}
-						}
-					}
-					if body, ok := httpConfig["body"].(map[string]interface{}); ok {
-						hook.HTTP.Body = body
-					}
-				}
-
-				// Convert Command config for hook
-				if cmdConfig, ok := hookMap["command"].(map[string]interface{}); ok {
-					hook.Command = &testcase.CommandTest{}
-					if cmd, ok := cmdConfig["cmd"].(string); ok {
-						hook.Command.Cmd = cmd
-					}
-					if args, ok := cmdConfig["args"].([]interface{}); ok {
-						for _, arg := range args {
-							if str, ok := arg.(string); ok {
-								hook.Command.Args = append(hook.Command.Args, str)
-							}
-						}
-					}
-					if timeout, ok := cmdConfig["timeout"].(float64); ok {
-						hook.Command.Timeout = int(timeout)
-					}
-				}
-
-				execTC.SetupHooks = append(execTC.SetupHooks, hook)
+				execTC.Assertions = append(execTC.Assertions, assertion)
 			}
 		}
 	}
 
-	// Convert Teardown Hooks
-	if tc.TeardownHooks != nil {
-		for _, h := range tc.TeardownHooks {
-			if hookMap, ok := h.(map[string]interface{}); ok {
-				hook := testcase.Hook{}
-				if hType, ok := hookMap["type"].(string); ok {
-					hook.Type = hType
-				}
-				if name, ok := hookMap["name"].(string); ok {
-					hook.Name = name
-				}
-				if saveResponse, ok := hookMap["saveResponse"].(string); ok {
-					hook.SaveResponse = saveResponse
-				}
-				if runOnFailure, ok := hookMap["runOnFailure"].(bool); ok {
-					hook.RunOnFailure = runOnFailure
-				}
-				if continueOnError, ok := hookMap["continueOnError"].(bool); ok {
-					hook.ContinueOnError = continueOnError
-				}
-
-				// Convert HTTP config for hook
-				if httpConfig, ok := hookMap["http"].(map[string]interface{}); ok {
-					hook.HTTP = &testcase.HTTPTest{}
-					if method, ok := httpConfig["method"].(string); ok {
-						hook.HTTP.Method = method
-					}
-					if path, ok := httpConfig["path"].(string); ok {
-						hook.HTTP.Path = path
-					}
-					if headers, ok := httpConfig["headers"].(map[string]interface{}); ok {
-						hook.HTTP.Headers = make(map[string]string)
-						for k, v := range headers {
-							if str, ok := v.(string); ok {
-								hook.HTTP.Headers[k] = str
-							}
-						}
-					}
-					if body, ok := httpConfig["body"].(map[string]interface{}); ok {
-						hook.HTTP.Body = body
-					}
-				}
-
-				// Convert Command config for hook
-				if cmdConfig, ok := hookMap["command"].(map[string]interface{}); ok {
-					hook.Command = &testcase.CommandTest{}
-					if cmd, ok := cmdConfig["cmd"].(string); ok {
-						hook.Command.Cmd = cmd
-					}
-					if args, ok := cmdConfig["args"].([]interface{}); ok {
-						for _, arg := range args {
-							if str, ok := arg.(string); ok {
-								hook.Command.Args = append(hook.Command.Args, str)
-							}
-						}
-					}
-					if timeout, ok := cmdConfig["timeout"].(float64); ok {
-						hook.Command.Timeout = int(timeout)
-					}
-				}
+	// Convert lifecycle hooks
+	execTC.SetupHooks = convertHooks(tc.SetupHooks)
+	execTC.TeardownHooks = convertHooks(tc.TeardownHooks)
 
-				execTC.TeardownHooks = append(execTC.TeardownHooks, hook)
-			}
-		}
+	// Convert variable extraction
+	if tc.Extract != nil {
+		data, _ := json.Marshal(tc.Extract)
+		json.Unmarshal(data, &execTC.Extract)
 	}
 
 	return execTC
@@ -665,8 +803,19 @@ func (s *testService) convertToModelResult(result *testcase.TestResult) *models.
 		}
 	}
 
-	// Store request/response as JSON
-	if result.Request != nil {
+	// Store per-assertion records, including passed and soft assertions
+	if result.Assertions != nil {
+		if data, err := json.Marshal(result.Assertions); err == nil {
+			var records []interface{}
+			json.Unmarshal(data, &records)
+			dbResult.Assertions = records
+		}
+	}
+
+	// Store load metrics, falling back to the request snapshot
+	if result.Metrics != nil {
+		dbResult.Metrics = result.Metrics
+	} else if result.Request != nil {
 		if data, err := json.Marshal(result.Request); err == nil {
 			var m map[string]interface{}
 			json.Unmarshal(data, &m)
@@ -674,5 +823,169 @@ func (s *testService) convertToModelResult(result *testcase.TestResult) *models.
 		}
 	}
 
+	// Store the response snapshot so failures can be inspected later
+	if result.Response != nil {
+		if data, err := json.Marshal(result.Response); err == nil {
+			var m map[string]interface{}
+			json.Unmarshal(data, &m)
+			dbResult.Response = m
+		}
+	}
+
+	// Store the variables extracted by the test
+	if len(result.Variables) > 0 {
+		dbResult.Variables = result.Variables
+	}
+
+	// Store the HTTP exchanges as a downloadable HAR artifact
+	if result.HAR != nil {
+		if data, err := json.Marshal(result.HAR); err == nil {
+			dbResult.ArtifactFiles = []models.TestArtifact{{
+				Name:        harArtifactName,
+				Type:        "har",
+				ContentType: "application/json",
+				Size:        len(data),
+				Content:     data,
+			}}
+		}
+	}
+	for _, artifact := range dbResult.ArtifactFiles {
+		dbResult.Artifacts = append(dbResult.Artifacts, map[string]interface{}{
+			"name":        artifact.Name,
+			"type":        artifact.Type,
+			"contentType": artifact.ContentType,
+			"size":        artifact.Size,
+		})
+	}
+
 	return dbResult
 }
+
+// convertHooks converts stored lifecycle hooks of a test case or group to executor hooks
+func convertHooks(hooks models.JSONArray) []testcase.Hook {
+	var result []testcase.Hook
+	for _, h := range hooks {
+		if hookMap, ok := h.(map[string]interface{}); ok {
+			hook := testcase.Hook{}
+			if hType, ok := hookMap["type"].(string); ok {
+				hook.Type = hType
+			}
+			if name, ok := hookMap["name"].(string); ok {
+				hook.Name = name
+			}
+			if saveResponse, ok := hookMap["saveResponse"].(string); ok {
+				hook.SaveResponse = saveResponse
+			}
+			if extract, ok := hookMap["extract"].(map[string]interface{}); ok {
+				hook.Extract = make(map[string]string)
+				for name, path := range extract {
+					if p, ok := path.(string); ok {
+						hook.Extract[name] = p
+					}
+				}
+			}
+			if runOnFailure, ok := hookMap["runOnFailure"].(bool); ok {
+				hook.RunOnFailure = runOnFailure
+			}
+			if continueOnError, ok := hookMap["continueOnError"].(bool); ok {
+				hook.ContinueOnError = continueOnError
+			}
+
+			// Convert HTTP config for hook
+			if httpConfig, ok := hookMap["http"].(map[string]interface{}); ok {
+				hook.HTTP = &testcase.HTTPTest{}
+				if method, ok := httpConfig["method"].(string); ok {
+					hook.HTTP.Method = method
+				}
+				if path, ok := httpConfig["path"].(string); ok {
+					hook.HTTP.Path = path
+				}
+				if headers, ok := httpConfig["headers"].(map[string]interface{}); ok {
+					hook.HTTP.Headers = make(map[string]string)
+					for k, v := range headers {
+						if str, ok := v.(string); ok {
+							hook.HTTP.Headers[k] = str
+						}
+					}
+				}
+				if body, ok := httpConfig["body"].(map[string]interface{}); ok {
+					hook.HTTP.Body = body
+				}
+				convertHTTPOptions(httpConfig, hook.HTTP)
+			}
+
+			// Convert Command config for hook
+			if cmdConfig, ok := hookMap["command"].(map[string]interface{}); ok {
+				hook.Command = &testcase.CommandTest{}
+				if cmd, ok := cmdConfig["cmd"].(string); ok {
+					hook.Command.Cmd = cmd
+				}
+				if args, ok := cmdConfig["args"].([]interface{}); ok {
+					for _, arg := range args {
+						if str, ok := arg.(string); ok {
+							hook.Command.Args = append(hook.Command.Args, str)
+						}
+					}
+				}
+				if timeout, ok := cmdConfig["timeout"].(float64); ok {
+					hook.Command.Timeout = int(timeout)
+				}
+				convertCommandOptions(cmdConfig, hook.Command)
+			}
+
+			// Convert SQL config for hook
+			if sqlConfig, ok := hookMap["sql"].(map[string]interface{}); ok {
+				hook.SQL = &testcase.DatabaseTest{}
+				data, _ := json.Marshal(sqlConfig)
+				json.Unmarshal(data, hook.SQL)
+			}
+
+			result = append(result, hook)
+		}
+	}
+	return result
+}
+
+// convertHTTPOptions copies the non-JSON body, response and auth options of an HTTP config
+func convertHTTPOptions(httpConfig map[string]interface{}, httpTest *testcase.HTTPTest) {
+	var options struct {
+		BodyType     string                  `json:"bodyType"`
+		RawBody      string                  `json:"rawBody"`
+		BodyFile     string                  `json:"bodyFile"`
+		Files        []testcase.HTTPFilePart `json:"files"`
+		ResponseType string                  `json:"responseType"`
+		Auth         *testcase.HTTPAuth      `json:"auth"`
+	}
+	data, _ := json.Marshal(httpConfig)
+	if err := json.Unmarshal(data, &options); err != nil {
+		return
+	}
+
+	httpTest.BodyType = options.BodyType
+	httpTest.RawBody = options.RawBody
+	httpTest.BodyFile = options.BodyFile
+	httpTest.Files = options.Files
+	httpTest.ResponseType = options.ResponseType
+	httpTest.Auth = options.Auth
+}
+
+// convertCommandOptions copies the working directory, environment, stdin, shell and workspace options of a command config
+func convertCommandOptions(cmdConfig map[string]interface{}, command *testcase.CommandTest) {
+	var options struct {
+		Cwd       string                     `json:"cwd"`
+		Env       map[string]string          `json:"env"`
+		Stdin     string                     `json:"stdin"`
+		Shell     bool                       `json:"shell"`
+		Workspace *testcase.CommandWorkspace `json:"workspace"`
+	}
+	data, _ := json.Marshal(cmdConfig)
+	if err := json.Unmarshal(data, &options); err != nil {
+		return
+	}
+
+	command.Cwd = options.Cwd
+	command.Env = options.Env
+	command.Stdin = options.Stdin
+	command.Shell = options.Shell
+	command.Workspace = options.Workspace
+}
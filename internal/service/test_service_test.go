@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"test-management-service/internal/models"
+)
+
+// TestExecuteTest_StoresResponse tests that a stored result keeps the response it was checked
+// against, so a failure can be inspected after the run
+func TestExecuteTest_StoresResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "cart", Value: "c-42"})
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-7")
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"error": "out of stock", "sku": "A-1"}`))
+	}))
+	defer server.Close()
+
+	svc, db := newRunService(t, server.URL, 1)
+	require.NoError(t, db.Create(httpTest("shop", "/checkout")).Error)
+
+	result, err := svc.ExecuteTest(context.Background(), "shop-checkout")
+	require.NoError(t, err)
+	assert.Equal(t, "failed", result.Status)
+
+	stored, err := svc.GetTestResult(result.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.Response)
+	assert.Equal(t, float64(http.StatusConflict), stored.Response["statusCode"])
+	assert.Equal(t, map[string]interface{}{"error": "out of stock", "sku": "A-1"}, stored.Response["body"])
+	assert.Equal(t, []interface{}{"req-7"}, stored.Response["headers"].(map[string]interface{})["X-Request-Id"])
+	assert.Equal(t, map[string]interface{}{"cart": "c-42"}, stored.Response["cookies"])
+	assert.Contains(t, stored.Response, "responseTimeMs")
+
+	// Tests that got no response store none
+	require.NoError(t, db.Create(&models.TestCase{TestID: "shop-invalid", GroupID: "shop", Name: "invalid", Type: "http"}).Error)
+	result, err = svc.ExecuteTest(context.Background(), "shop-invalid")
+	require.NoError(t, err)
+	stored, err = svc.GetTestResult(result.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "error", stored.Status)
+	assert.Nil(t, stored.Response)
+}
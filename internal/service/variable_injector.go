@@ -41,6 +41,24 @@ func (vi *VariableInjector) GetActiveEnvironmentVariables() (map[string]string,
 	return result, nil
 }
 
+// GetActiveEnvironmentSecretKeys 获取激活环境中标记为密钥的变量名
+func (vi *VariableInjector) GetActiveEnvironmentSecretKeys() ([]string, error) {
+	activeEnv, err := vi.envService.GetActiveEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	return activeEnv.SecretKeys(), nil
+}
+
+// GetActiveEnvironmentID 获取激活环境的ID
+func (vi *VariableInjector) GetActiveEnvironmentID() (string, error) {
+	activeEnv, err := vi.envService.GetActiveEnvironment()
+	if err != nil {
+		return "", err
+	}
+	return activeEnv.EnvID, nil
+}
+
 // InjectVariables 注入环境变量到配置中
 // 支持三层变量优先级: envVars < workflowVars < inlineVars
 func (vi *VariableInjector) InjectVariables(
@@ -195,6 +213,7 @@ func (vi *VariableInjector) InjectHTTPVariables(httpConfig *testcase.HTTPTest) e
 		"path":    httpConfig.Path,
 		"headers": httpConfig.Headers,
 		"body":    httpConfig.Body,
+		"rawBody": httpConfig.RawBody,
 	}
 
 	// Inject variables
@@ -226,6 +245,9 @@ func (vi *VariableInjector) InjectHTTPVariables(httpConfig *testcase.HTTPTest) e
 	if body, ok := injectedMap["body"].(map[string]interface{}); ok {
 		httpConfig.Body = body
 	}
+	if rawBody, ok := injectedMap["rawBody"].(string); ok {
+		httpConfig.RawBody = rawBody
+	}
 
 	return nil
 }
@@ -20,6 +20,7 @@ type WorkflowService interface {
 	ExecuteWorkflow(workflowID string, variables map[string]interface{}) (*models.WorkflowRun, error)
 	GetWorkflowRun(runID string) (*models.WorkflowRun, error)
 	ListWorkflowRuns(workflowID string, limit, offset int) ([]models.WorkflowRun, int64, error)
+	CancelWorkflowRun(runID string) (*models.WorkflowRun, error)
 
 	GetWorkflowTestCases(workflowID string) ([]models.TestCase, error)
 	GetStepExecutions(runID string) ([]models.WorkflowStepExecution, error)
@@ -185,6 +186,22 @@ func (s *workflowService) GetWorkflowRun(runID string) (*models.WorkflowRun, err
 	return s.workflowRunRepo.GetByRunID(runID)
 }
 
+// CancelWorkflowRun cancels a workflow run in progress and returns it once its record is saved
+func (s *workflowService) CancelWorkflowRun(runID string) (*models.WorkflowRun, error) {
+	run, err := s.workflowRunRepo.GetByRunID(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	done, ok := s.executor.Cancel(runID)
+	if !ok {
+		return nil, fmt.Errorf("%w: workflow run %s is %s", ErrRunNotActive, runID, run.Status)
+	}
+	<-done
+
+	return s.workflowRunRepo.GetByRunID(runID)
+}
+
 func (s *workflowService) ListWorkflowRuns(workflowID string, limit, offset int) ([]models.WorkflowRun, int64, error) {
 	runs, err := s.workflowRunRepo.ListByWorkflowID(workflowID, 0)
 	if err != nil {
@@ -0,0 +1,32 @@
+package testcase
+
+import "strings"
+
+// recordAssertion stores the outcome of an assertion in the result.
+// Empty failure messages are ignored; any remaining message fails the test unless the assertion is soft,
+// in which case it is only reported in the assertion record.
+func recordAssertion(result *TestResult, assertion Assertion, actual interface{}, failures ...string) {
+	var messages []string
+	for _, failure := range failures {
+		if failure != "" {
+			messages = append(messages, failure)
+		}
+	}
+
+	result.Assertions = append(result.Assertions, AssertionResult{
+		Type:     assertion.Type,
+		Path:     assertion.Path,
+		Operator: assertion.Operator,
+		Expected: assertion.Expected,
+		Actual:   actual,
+		Passed:   len(messages) == 0,
+		Soft:     assertion.Soft,
+		Message:  strings.Join(messages, "; "),
+	})
+
+	if len(messages) == 0 || assertion.Soft {
+		return
+	}
+	result.Status = "failed"
+	result.Failures = append(result.Failures, messages...)
+}
@@ -0,0 +1,66 @@
+package testcase
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAssertionResults_RecordsAndSoftAssertions tests that every assertion is recorded and soft failures don't fail the test
+func TestAssertionResults_RecordsAndSoftAssertions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"version": "1.2.0", "items": [1, 2, 3]}`))
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	result := executor.Execute(&TestCase{
+		ID:   "records-soft",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "GET", Path: "/info"},
+		Assertions: []Assertion{
+			{Type: "status_code", Expected: 200},
+			{Type: "json_path", Path: "$.items", Operator: "length", Expected: 3},
+			{Type: "json_path", Path: "$.version", Expected: "2.0.0", Soft: true},
+		},
+	})
+
+	assert.Equal(t, "passed", result.Status, "failures: %v", result.Failures)
+	assert.Empty(t, result.Failures)
+	require.Len(t, result.Assertions, 3)
+
+	assert.Equal(t, AssertionResult{Type: "status_code", Expected: 200, Actual: 200, Passed: true}, result.Assertions[0])
+	assert.True(t, result.Assertions[1].Passed)
+	assert.Equal(t, []interface{}{1.0, 2.0, 3.0}, result.Assertions[1].Actual)
+
+	soft := result.Assertions[2]
+	assert.False(t, soft.Passed)
+	assert.True(t, soft.Soft)
+	assert.Equal(t, "1.2.0", soft.Actual)
+	assert.Contains(t, soft.Message, "expected 2.0.0")
+}
+
+// TestAssertionResults_HardFailure tests that a failed hard assertion is both recorded and listed in failures
+func TestAssertionResults_HardFailure(t *testing.T) {
+	executor := NewExecutor("")
+	result := executor.Execute(&TestCase{
+		ID:      "records-hard",
+		Type:    "command",
+		Command: &CommandTest{Cmd: "sh", Args: []string{"-c", "echo hello; exit 3"}},
+		Assertions: []Assertion{
+			{Type: "exit_code", Expected: 0},
+			{Type: "stdout", Expected: "hello"},
+		},
+	})
+
+	assert.Equal(t, "failed", result.Status)
+	require.Len(t, result.Assertions, 2)
+	assert.False(t, result.Assertions[0].Passed)
+	assert.Equal(t, 3, result.Assertions[0].Actual)
+	assert.Equal(t, []string{result.Assertions[0].Message}, result.Failures)
+	assert.True(t, result.Assertions[1].Passed)
+}
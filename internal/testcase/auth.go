@@ -0,0 +1,332 @@
+package testcase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// oauthExpirySkew renews cached OAuth2 tokens shortly before they expire
+	oauthExpirySkew = 10 * time.Second
+	// oauthTokenTimeout limits a token request, which is shared by the tests waiting for it
+	oauthTokenTimeout = 30 * time.Second
+)
+
+// referencePattern matches {{NAME}} references to variables in credentials and certificates
+var referencePattern = regexp.MustCompile(`\{\{([a-zA-Z0-9_]+)\}\}`)
+
+// hmacAlgorithms are the hash functions supported for HMAC signing
+var hmacAlgorithms = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// oauthToken is a cached OAuth2 access token
+type oauthToken struct {
+	accessToken string
+	expiresAt   time.Time
+}
+
+// oauthTokenCache caches OAuth2 access tokens until they expire
+type oauthTokenCache struct {
+	mu       sync.Mutex
+	tokens   map[string]oauthToken
+	inflight map[string]*oauthFetch
+}
+
+// oauthFetch is a token request in progress, shared by the callers waiting for the same key
+type oauthFetch struct {
+	done        chan struct{}
+	accessToken string
+	err         error
+}
+
+func newOAuthTokenCache() *oauthTokenCache {
+	return &oauthTokenCache{tokens: make(map[string]oauthToken), inflight: make(map[string]*oauthFetch)}
+}
+
+// token returns the cached token for key, or fetches and caches a new one.
+// Concurrent requests for the same key share a single token request; requests for
+// other keys are not blocked by it. The shared request is not tied to any caller's ctx,
+// so one caller being cancelled does not fail the others; each caller stops waiting when
+// its own ctx is done. Tokens without a lifetime are not cached.
+func (c *oauthTokenCache) token(ctx context.Context, key string, fetch func(ctx context.Context) (string, time.Duration, error)) (string, error) {
+	c.mu.Lock()
+	if cached, ok := c.tokens[key]; ok && time.Now().Before(cached.expiresAt) {
+		c.mu.Unlock()
+		return cached.accessToken, nil
+	}
+	f, ok := c.inflight[key]
+	if !ok {
+		f = &oauthFetch{done: make(chan struct{})}
+		c.inflight[key] = f
+		go c.fetch(ctx, key, f, fetch)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.accessToken, f.err
+	case <-ctx.Done():
+		return "", fmt.Errorf("auth: interrupted waiting for oauth2 token: %w", context.Cause(ctx))
+	}
+}
+
+// fetch runs a shared token request with its own timeout and caches the token it returns
+func (c *oauthTokenCache) fetch(ctx context.Context, key string, f *oauthFetch, fetch func(ctx context.Context) (string, time.Duration, error)) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), oauthTokenTimeout)
+	defer cancel()
+	accessToken, expiresIn, err := fetch(ctx)
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	if err == nil && expiresIn > 0 {
+		c.tokens[key] = oauthToken{accessToken: accessToken, expiresAt: time.Now().Add(expiresIn - oauthExpirySkew)}
+	} else {
+		delete(c.tokens, key)
+	}
+	c.mu.Unlock()
+
+	f.accessToken, f.err = accessToken, err
+	close(f.done)
+}
+
+// resolveAuth returns a copy of the auth config with {{NAME}} references resolved
+func (e *UnifiedTestExecutor) resolveAuth(auth *HTTPAuth, hookCtx map[string]interface{}) (*HTTPAuth, error) {
+	if auth == nil {
+		return nil, nil
+	}
+
+	resolved := *auth
+	missing := e.resolveReferences(hookCtx,
+		&resolved.Username, &resolved.Password, &resolved.Token,
+		&resolved.Key, &resolved.Name,
+		&resolved.TokenURL, &resolved.ClientID, &resolved.ClientSecret, &resolved.Scope,
+		&resolved.Secret,
+	)
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("auth: variable %s is not set", strings.Join(missing, ", "))
+	}
+	return &resolved, nil
+}
+
+// resolveReferences replaces {{NAME}} references in the given fields with values saved by
+// setup hooks, the active environment's variables, or process environment variables.
+// It returns the names that could not be resolved.
+func (e *UnifiedTestExecutor) resolveReferences(hookCtx map[string]interface{}, fields ...*string) []string {
+	var vars map[string]interface{}
+	var missing []string
+	for _, field := range fields {
+		if !strings.Contains(*field, "{{") {
+			continue
+		}
+		if vars == nil {
+			vars = e.resolvedVariables(hookCtx)
+		}
+		*field = referencePattern.ReplaceAllStringFunc(*field, func(placeholder string) string {
+			name := placeholder[2 : len(placeholder)-2]
+			if value, ok := vars[name]; ok {
+				return stringValue(value)
+			}
+			if value, ok := os.LookupEnv(name); ok {
+				return value
+			}
+			missing = append(missing, name)
+			return placeholder
+		})
+	}
+	return missing
+}
+
+// applyAuth authenticates a request with a resolved auth config.
+// It runs after the body and headers are set, since HMAC signatures cover them.
+func (e *UnifiedTestExecutor) applyAuth(req *http.Request, auth *HTTPAuth, body *httpRequestBody) error {
+	if auth == nil {
+		return nil
+	}
+
+	switch strings.ToLower(auth.Type) {
+	case "basic":
+		req.SetBasicAuth(auth.Username, auth.Password)
+
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+auth.Token)
+
+	case "apikey":
+		name := auth.Name
+		if name == "" {
+			name = "X-API-Key"
+		}
+		redactHARName(req.Context(), name)
+		switch strings.ToLower(auth.In) {
+		case "", "header":
+			req.Header.Set(name, auth.Key)
+		case "query":
+			query := req.URL.Query()
+			query.Set(name, auth.Key)
+			req.URL.RawQuery = query.Encode()
+		default:
+			return fmt.Errorf("auth: unsupported apikey location: %s", auth.In)
+		}
+
+	case "oauth2":
+		token, err := e.oauthAccessToken(req.Context(), auth)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+
+	case "hmac":
+		return signHMAC(req, auth, body, time.Now())
+
+	default:
+		return fmt.Errorf("auth: unsupported type: %s", auth.Type)
+	}
+	return nil
+}
+
+// oauthAccessToken returns an OAuth2 access token, requesting one from the token endpoint
+// when no unexpired token is cached for the active environment and client
+func (e *UnifiedTestExecutor) oauthAccessToken(ctx context.Context, auth *HTTPAuth) (string, error) {
+	grantType := auth.GrantType
+	if grantType == "" {
+		grantType = "client_credentials"
+	}
+
+	form := url.Values{"grant_type": {grantType}}
+	switch grantType {
+	case "client_credentials":
+	case "password":
+		form.Set("username", auth.Username)
+		form.Set("password", auth.Password)
+	default:
+		return "", fmt.Errorf("auth: unsupported oauth2 grant type: %s", grantType)
+	}
+	if auth.Scope != "" {
+		form.Set("scope", auth.Scope)
+	}
+
+	tokenURL := auth.TokenURL
+	if tokenURL == "" {
+		return "", fmt.Errorf("auth: oauth2 requires tokenUrl")
+	}
+	if strings.HasPrefix(tokenURL, "/") {
+		tokenURL = e.baseURL + tokenURL
+	}
+
+	return e.tokenCache.token(ctx, e.oauthCacheKey(auth, tokenURL, grantType), func(ctx context.Context) (string, time.Duration, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", 0, fmt.Errorf("auth: failed to create token request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Accept", "application/json")
+		if auth.ClientID != "" {
+			req.SetBasicAuth(auth.ClientID, auth.ClientSecret)
+		}
+
+		client, err := e.httpClient()
+		if err != nil {
+			return "", 0, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", 0, fmt.Errorf("auth: token request failed: %w", err)
+		}
+		defer resp.Body.Close()
+
+		data, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return "", 0, fmt.Errorf("auth: token request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+		}
+
+		var payload struct {
+			AccessToken string      `json:"access_token"`
+			ExpiresIn   json.Number `json:"expires_in"`
+		}
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return "", 0, fmt.Errorf("auth: invalid token response: %w", err)
+		}
+		if payload.AccessToken == "" {
+			return "", 0, fmt.Errorf("auth: token response has no access_token")
+		}
+
+		seconds, _ := strconv.ParseFloat(payload.ExpiresIn.String(), 64)
+		fmt.Printf("[Auth] Obtained oauth2 token from %s (expires in %vs)\n", tokenURL, seconds)
+		return payload.AccessToken, time.Duration(seconds * float64(time.Second)), nil
+	})
+}
+
+// oauthCacheKey identifies a token by the active environment, the endpoint and the credentials used to obtain it
+func (e *UnifiedTestExecutor) oauthCacheKey(auth *HTTPAuth, tokenURL, grantType string) string {
+	envID := ""
+	if e.variableInjector != nil {
+		envID, _ = e.variableInjector.GetActiveEnvironmentID()
+	}
+	secrets := sha256.Sum256([]byte(auth.ClientSecret + "\x00" + auth.Password))
+	return strings.Join([]string{
+		envID, tokenURL, grantType, auth.ClientID, auth.Username, auth.Scope, hex.EncodeToString(secrets[:]),
+	}, "\x00")
+}
+
+// signHMAC signs a request with an HMAC over its method, path and query, timestamp and body digest:
+//
+//	METHOD\n/path?query\ntimestamp\nhex(sha256(body))
+//
+// The hex-encoded signature is sent in the signature header, the Unix timestamp in
+// X-Timestamp and the key id, if any, in X-Key-Id.
+func signHMAC(req *http.Request, auth *HTTPAuth, body *httpRequestBody, now time.Time) error {
+	algorithm := strings.ToLower(auth.Algorithm)
+	if algorithm == "" {
+		algorithm = "sha256"
+	}
+	newHash, ok := hmacAlgorithms[algorithm]
+	if !ok {
+		return fmt.Errorf("auth: unsupported hmac algorithm: %s", auth.Algorithm)
+	}
+	if auth.Secret == "" {
+		return fmt.Errorf("auth: hmac requires secret")
+	}
+
+	var payload []byte
+	if body != nil {
+		payload = body.Data
+	}
+	bodyDigest := sha256.Sum256(payload)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	canonical := strings.Join([]string{
+		req.Method, req.URL.RequestURI(), timestamp, hex.EncodeToString(bodyDigest[:]),
+	}, "\n")
+
+	mac := hmac.New(newHash, []byte(auth.Secret))
+	mac.Write([]byte(canonical))
+
+	header := auth.Header
+	if header == "" {
+		header = "X-Signature"
+	}
+	redactHARName(req.Context(), header)
+	req.Header.Set(header, hex.EncodeToString(mac.Sum(nil)))
+	req.Header.Set("X-Timestamp", timestamp)
+	if auth.Key != "" {
+		req.Header.Set("X-Key-Id", auth.Key)
+	}
+	return nil
+}
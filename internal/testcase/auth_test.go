@@ -0,0 +1,309 @@
+package testcase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEnvironmentInjector serves variables of a switchable active environment
+type fakeEnvironmentInjector struct {
+	envID string
+	vars  map[string]map[string]string
+}
+
+func (f *fakeEnvironmentInjector) InjectHTTPVariables(config *HTTPTest) error       { return nil }
+func (f *fakeEnvironmentInjector) InjectCommandVariables(config *CommandTest) error { return nil }
+func (f *fakeEnvironmentInjector) GetActiveEnvironmentVariables() (map[string]string, error) {
+	return f.vars[f.envID], nil
+}
+func (f *fakeEnvironmentInjector) GetActiveEnvironmentID() (string, error) { return f.envID, nil }
+
+// echoAuthServer echoes the credentials a request carries
+func echoAuthServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"authorization": r.Header.Get("Authorization"),
+			"apiKey":        r.Header.Get("X-API-Key"),
+			"queryKey":      r.URL.Query().Get("api_key"),
+		})
+	}))
+}
+
+// TestAuth_StaticSchemes tests basic, bearer and API key auth with credentials from environment variables
+func TestAuth_StaticSchemes(t *testing.T) {
+	server := echoAuthServer()
+	defer server.Close()
+	t.Setenv("AUTH_TEST_PASSWORD", "s3cret")
+
+	executor := NewExecutor(server.URL)
+	cases := []struct {
+		auth     *HTTPAuth
+		path     string
+		expected string
+	}{
+		{&HTTPAuth{Type: "basic", Username: "alice", Password: "{{AUTH_TEST_PASSWORD}}"}, "$.authorization", "Basic YWxpY2U6czNjcmV0"},
+		{&HTTPAuth{Type: "bearer", Token: "tok-{{AUTH_TEST_PASSWORD}}"}, "$.authorization", "Bearer tok-s3cret"},
+		{&HTTPAuth{Type: "apikey", Key: "k-123"}, "$.apiKey", "k-123"},
+		{&HTTPAuth{Type: "apikey", Key: "k-456", Name: "api_key", In: "query"}, "$.queryKey", "k-456"},
+	}
+
+	for _, c := range cases {
+		result := executor.Execute(&TestCase{
+			ID:         "auth-" + c.auth.Type,
+			Type:       "http",
+			HTTP:       &HTTPTest{Method: "GET", Path: "/echo", Auth: c.auth},
+			Assertions: []Assertion{{Type: "json_path", Path: c.path, Expected: c.expected}},
+		})
+		assert.Equal(t, "passed", result.Status, "%s: failures: %v, error: %s", c.auth.Type, result.Failures, result.Error)
+		assert.Equal(t, c.auth.Type, result.Request["auth"])
+	}
+
+	result := executor.Execute(&TestCase{
+		ID:   "auth-missing",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "GET", Path: "/echo", Auth: &HTTPAuth{Type: "bearer", Token: "{{AUTH_TEST_UNSET_TOKEN}}"}},
+	})
+	assert.Equal(t, "error", result.Status)
+	assert.Equal(t, "auth: variable AUTH_TEST_UNSET_TOKEN is not set", result.Error)
+}
+
+// TestAuth_OAuth2TokenCaching tests that tokens are reused until expiry and cached per environment
+func TestAuth_OAuth2TokenCaching(t *testing.T) {
+	var tokenRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/token":
+			n := atomic.AddInt32(&tokenRequests, 1)
+			clientID, clientSecret, _ := r.BasicAuth()
+			r.ParseForm()
+			if clientSecret != "secret-"+clientID {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error": "invalid_client"}`))
+				return
+			}
+			expiresIn := 3600
+			if r.PostForm.Get("scope") == "short" {
+				expiresIn = 5
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token": r.PostForm.Get("grant_type") + "-" + clientID + "-" + string(rune('0'+n)),
+				"token_type":   "Bearer",
+				"expires_in":   expiresIn,
+			})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"authorization": r.Header.Get("Authorization")})
+		}
+	}))
+	defer server.Close()
+
+	injector := &fakeEnvironmentInjector{
+		envID: "dev",
+		vars: map[string]map[string]string{
+			"dev":     {"CLIENT_ID": "dev-app", "CLIENT_SECRET": "secret-dev-app"},
+			"staging": {"CLIENT_ID": "stg-app", "CLIENT_SECRET": "secret-stg-app"},
+		},
+	}
+	executor := NewExecutorWithInjector(server.URL, nil, nil, nil, injector)
+	auth := &HTTPAuth{Type: "oauth2", TokenURL: "/oauth/token", ClientID: "{{CLIENT_ID}}", ClientSecret: "{{CLIENT_SECRET}}"}
+
+	run := func(auth *HTTPAuth, expected string) {
+		result := executor.Execute(&TestCase{
+			ID:         "auth-oauth2",
+			Type:       "http",
+			HTTP:       &HTTPTest{Method: "GET", Path: "/me", Auth: auth},
+			Assertions: []Assertion{{Type: "json_path", Path: "$.authorization", Expected: expected}},
+		})
+		assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+	}
+
+	run(auth, "Bearer client_credentials-dev-app-1")
+	run(auth, "Bearer client_credentials-dev-app-1")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&tokenRequests))
+
+	// Switching environments uses that environment's credentials and token
+	injector.envID = "staging"
+	run(auth, "Bearer client_credentials-stg-app-2")
+	injector.envID = "dev"
+	run(auth, "Bearer client_credentials-dev-app-1")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&tokenRequests))
+
+	// The password grant is a separate token, and tokens about to expire are not reused
+	password := &HTTPAuth{Type: "oauth2", TokenURL: "/oauth/token", GrantType: "password", ClientID: "cli", ClientSecret: "secret-cli", Username: "alice", Password: "pw", Scope: "short"}
+	run(password, "Bearer password-cli-3")
+	run(password, "Bearer password-cli-4")
+
+	// Setup hooks authenticate the same way
+	result := executor.Execute(&TestCase{
+		ID:   "auth-oauth2-hook",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "GET", Path: "/me"},
+		SetupHooks: []Hook{
+			{Type: "http", Name: "whoami", HTTP: &HTTPTest{Method: "GET", Path: "/me", Auth: auth}, Extract: map[string]string{"seen": "$.body.authorization"}},
+		},
+		Assertions: []Assertion{{Type: "expr", Expected: `vars.seen == "Bearer client_credentials-dev-app-1"`}},
+	})
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&tokenRequests))
+
+	result = executor.Execute(&TestCase{
+		ID:   "auth-oauth2-denied",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "GET", Path: "/me", Auth: &HTTPAuth{Type: "oauth2", TokenURL: "/oauth/token", ClientID: "cli", ClientSecret: "wrong"}},
+	})
+	assert.Equal(t, "error", result.Status)
+	assert.Contains(t, result.Error, "token request failed with status 401")
+}
+
+// TestOAuthTokenCache_ConcurrentFetches tests that concurrent requests for a key share one
+// token request without blocking requests for other keys
+func TestOAuthTokenCache_ConcurrentFetches(t *testing.T) {
+	cache := newOAuthTokenCache()
+	release := make(chan struct{})
+	var fetches int32
+	slowFetch := func(context.Context) (string, time.Duration, error) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		return "slow-token", time.Hour, nil
+	}
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 5)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			token, err := cache.token(context.Background(), "slow", slowFetch)
+			assert.NoError(t, err)
+			tokens[i] = token
+		}(i)
+	}
+
+	// Another key is fetched while the slow request is still in flight
+	token, err := cache.token(context.Background(), "fast", func(context.Context) (string, time.Duration, error) { return "fast-token", time.Hour, nil })
+	require.NoError(t, err)
+	assert.Equal(t, "fast-token", token)
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+	for _, token := range tokens {
+		assert.Equal(t, "slow-token", token)
+	}
+}
+
+// TestOAuthTokenCache_CallerCancelled tests that the shared token request outlives the caller
+// that started it and that a cancelled caller stops waiting for it
+func TestOAuthTokenCache_CallerCancelled(t *testing.T) {
+	cache := newOAuthTokenCache()
+	release := make(chan struct{})
+	fetchErr := make(chan error, 1)
+	fetch := func(ctx context.Context) (string, time.Duration, error) {
+		select {
+		case <-release:
+			return "shared-token", time.Hour, nil
+		case <-ctx.Done():
+			fetchErr <- ctx.Err()
+			return "", 0, ctx.Err()
+		}
+	}
+
+	// The first caller starts the request and is cancelled while it is in flight
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := cache.token(firstCtx, "client", fetch)
+		first <- err
+	}()
+	waiter := make(chan string, 1)
+	require.Eventually(t, func() bool {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		return cache.inflight["client"] != nil
+	}, time.Second, time.Millisecond)
+	go func() {
+		token, err := cache.token(context.Background(), "client", fetch)
+		assert.NoError(t, err)
+		waiter <- token
+	}()
+
+	cancelFirst()
+	select {
+	case err := <-first:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("a cancelled caller kept waiting for the token")
+	}
+
+	close(release)
+	select {
+	case token := <-waiter:
+		assert.Equal(t, "shared-token", token)
+	case <-time.After(time.Second):
+		t.Fatal("the waiting caller did not receive the token")
+	}
+	select {
+	case err := <-fetchErr:
+		t.Fatalf("the shared request was interrupted: %v", err)
+	default:
+	}
+
+	// The token was cached for later callers
+	token, err := cache.token(context.Background(), "client", func(context.Context) (string, time.Duration, error) {
+		return "", 0, errors.New("unexpected token request")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "shared-token", token)
+}
+
+// TestAuth_HMACSignature tests that the server can verify HMAC request signatures
+func TestAuth_HMACSignature(t *testing.T) {
+	secret := "hmac-secret"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		digest := sha256.Sum256(body)
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(r.Method + "\n" + r.URL.RequestURI() + "\n" + r.Header.Get("X-Timestamp") + "\n" + hex.EncodeToString(digest[:])))
+
+		if !hmac.Equal([]byte(hex.EncodeToString(mac.Sum(nil))), []byte(r.Header.Get("X-Signature"))) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keyId": r.Header.Get("X-Key-Id")})
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	result := executor.Execute(&TestCase{
+		ID:   "auth-hmac",
+		Type: "http",
+		HTTP: &HTTPTest{
+			Method: "POST",
+			Path:   "/orders?dry_run=true",
+			Body:   map[string]interface{}{"sku": "A-1", "qty": 2},
+			Auth:   &HTTPAuth{Type: "hmac", Key: "key-1", Secret: secret},
+		},
+		Assertions: []Assertion{
+			{Type: "status_code", Expected: 200},
+			{Type: "json_path", Path: "$.keyId", Expected: "key-1"},
+		},
+	})
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+
+	req, err := http.NewRequest("GET", server.URL, nil)
+	require.NoError(t, err)
+	assert.EqualError(t, signHMAC(req, &HTTPAuth{Type: "hmac", Secret: secret, Algorithm: "md5"}, nil, time.Now()), "auth: unsupported hmac algorithm: md5")
+}
@@ -0,0 +1,169 @@
+package testcase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExecuteContext_TestTimeout tests that a test timeout stops an in-flight request and still runs teardown hooks
+func TestExecuteContext_TestTimeout(t *testing.T) {
+	var cleanups int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cleanup":
+			atomic.AddInt32(&cleanups, 1)
+		default:
+			select {
+			case <-time.After(5 * time.Second):
+			case <-r.Context().Done():
+			}
+		}
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	start := time.Now()
+	result := executor.Execute(&TestCase{
+		ID:      "timeout-http",
+		Type:    "http",
+		Timeout: 1,
+		HTTP:    &HTTPTest{Method: "GET", Path: "/slow"},
+		TeardownHooks: []Hook{
+			{Type: "http", Name: "cleanup", HTTP: &HTTPTest{Method: "POST", Path: "/cleanup"}, RunOnFailure: true},
+		},
+	})
+
+	assert.Less(t, time.Since(start), 3*time.Second)
+	assert.Equal(t, "timeout", result.Status)
+	assert.Equal(t, "test timed out after 1s", result.Error)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&cleanups))
+}
+
+// TestExecuteContext_Cancelled tests that cancelling the context kills a running command
+func TestExecuteContext_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	executor := NewExecutor("")
+	start := time.Now()
+	result := executor.ExecuteContext(ctx, &TestCase{
+		ID:      "cancelled-command",
+		Type:    "command",
+		Command: &CommandTest{Cmd: "sleep", Args: []string{"5"}},
+	})
+
+	assert.Less(t, time.Since(start), 3*time.Second)
+	assert.Equal(t, "cancelled", result.Status)
+	assert.Equal(t, "test cancelled", result.Error)
+
+	// A command's own timeout is reported as a timeout rather than a failure
+	result = executor.Execute(&TestCase{
+		ID:      "command-timeout",
+		Type:    "command",
+		Command: &CommandTest{Cmd: "sleep", Args: []string{"5"}, Timeout: 1},
+	})
+	assert.Equal(t, "timeout", result.Status)
+	assert.Equal(t, "command timed out after 1s", result.Error)
+}
+
+// TestExecuteContext_ShellCommandKilled tests that cancellation and a command timeout kill the
+// processes started by a shell command, not only the shell
+func TestExecuteContext_ShellCommandKilled(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "done")
+	script := "sleep 3; echo done > " + marker
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	executor := NewExecutor("")
+	start := time.Now()
+	result := executor.ExecuteContext(ctx, &TestCase{
+		ID:      "cancelled-shell",
+		Type:    "command",
+		Command: &CommandTest{Cmd: script, Shell: true},
+	})
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Equal(t, "cancelled", result.Status)
+
+	start = time.Now()
+	result = executor.Execute(&TestCase{
+		ID:      "shell-timeout",
+		Type:    "command",
+		Command: &CommandTest{Cmd: script, Shell: true, Timeout: 1},
+	})
+	assert.Less(t, time.Since(start), 2500*time.Millisecond)
+	assert.Equal(t, "timeout", result.Status)
+	assert.Equal(t, "command timed out after 1s", result.Error)
+
+	start = time.Now()
+	result = executor.Execute(&TestCase{
+		ID:         "shell-hook-timeout",
+		Type:       "command",
+		Command:    &CommandTest{Cmd: "echo", Args: []string{"never"}},
+		SetupHooks: []Hook{{Type: "command", Name: "seed", Command: &CommandTest{Cmd: script, Shell: true, Timeout: 1}}},
+	})
+	assert.Less(t, time.Since(start), 2500*time.Millisecond)
+	assert.NotEqual(t, "passed", result.Status)
+
+	// A script that exits while a process it started still holds its output is not held up by it
+	start = time.Now()
+	result = executor.Execute(&TestCase{
+		ID:      "shell-background",
+		Type:    "command",
+		Command: &CommandTest{Cmd: "sleep 3 & echo started", Shell: true},
+	})
+	assert.Less(t, time.Since(start), 2500*time.Millisecond)
+	assert.Equal(t, "passed", result.Status, result.Error)
+	assert.Equal(t, "started\n", result.Response["stdout"])
+
+	// The killed scripts never reach their last command
+	time.Sleep(3 * time.Second)
+	assert.NoFileExists(t, marker)
+}
+
+// TestExecuteContext_SetupHookInterrupted tests that an interrupted setup hook marks the test, not an error
+func TestExecuteContext_SetupHookInterrupted(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	executor := NewExecutor("")
+	result := executor.ExecuteContext(ctx, &TestCase{
+		ID:         "interrupted-setup",
+		Type:       "command",
+		Command:    &CommandTest{Cmd: "echo", Args: []string{"never"}},
+		SetupHooks: []Hook{{Type: "command", Name: "seed", Command: &CommandTest{Cmd: "sleep", Args: []string{"5"}}}},
+	})
+
+	assert.Equal(t, "timeout", result.Status)
+	assert.Nil(t, result.Response)
+}
+
+// TestExecuteContext_CommandStartFailure tests that a command that cannot be started is an error
+// for tests and a failure for hooks
+func TestExecuteContext_CommandStartFailure(t *testing.T) {
+	executor := NewExecutor("")
+	result := executor.Execute(&TestCase{
+		ID:      "missing-command",
+		Type:    "command",
+		Timeout: 1,
+		Command: &CommandTest{Cmd: "no-such-command-xyz"},
+	})
+	assert.Equal(t, "error", result.Status)
+	assert.Contains(t, result.Error, "command failed:")
+
+	result = executor.Execute(&TestCase{
+		ID:         "missing-hook-command",
+		Type:       "command",
+		Command:    &CommandTest{Cmd: "echo", Args: []string{"never"}},
+		SetupHooks: []Hook{{Type: "command", Name: "seed", Command: &CommandTest{Cmd: "no-such-command-xyz"}}},
+	})
+	assert.NotEqual(t, "passed", result.Status)
+	assert.Nil(t, result.Response)
+}
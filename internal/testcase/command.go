@@ -0,0 +1,269 @@
+package testcase
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// secretMask replaces secret values in recorded command environments and output
+	secretMask = "******"
+	// workspaceVariable is the environment variable holding the path of a command's workspace
+	workspaceVariable = "WORKSPACE"
+	// commandWaitDelay bounds how long a command's output is read after it exits or is killed,
+	// in case processes it left behind still hold its stdout or stderr open
+	commandWaitDelay = 2 * time.Second
+)
+
+// preparedCommand is a command ready to run, with the values to mask in what is recorded about it
+type preparedCommand struct {
+	cmd     *exec.Cmd
+	stdout  bytes.Buffer
+	stderr  bytes.Buffer
+	env     []string // configured environment variables as KEY=value
+	stdin   string
+	secrets []string
+	cleanup func()
+}
+
+// prepareCommand builds the process for a command config: environment variables with their
+// {{NAME}} references resolved, stdin, shell mode and the temporary workspace.
+// The caller runs cleanup once the process has exited.
+func (e *UnifiedTestExecutor) prepareCommand(config *CommandTest, hookCtx map[string]interface{}) (*preparedCommand, error) {
+	prepared := &preparedCommand{cleanup: func() {}}
+
+	env, err := e.commandEnv(config, hookCtx, prepared)
+	if err != nil {
+		return nil, err
+	}
+	prepared.env = env
+
+	prepared.stdin = config.Stdin
+	if strings.Contains(prepared.stdin, "{{") {
+		prepared.addSecrets(e.secretReferences(hookCtx, prepared.stdin)...)
+		if missing := e.resolveReferences(hookCtx, &prepared.stdin); len(missing) > 0 {
+			return nil, fmt.Errorf("command stdin: variable %s is not set", strings.Join(missing, ", "))
+		}
+	}
+
+	if config.Shell {
+		prepared.cmd = shellCommand(config.Cmd, config.Args)
+	} else {
+		prepared.cmd = exec.Command(config.Cmd, config.Args...)
+	}
+	startInProcessGroup(prepared.cmd)
+	prepared.cmd.WaitDelay = commandWaitDelay
+	prepared.cmd.Dir = config.Cwd
+	prepared.cmd.Stdout = &prepared.stdout
+	prepared.cmd.Stderr = &prepared.stderr
+	if prepared.stdin != "" {
+		prepared.cmd.Stdin = strings.NewReader(prepared.stdin)
+	}
+
+	if config.Workspace != nil {
+		dir, err := createWorkspace(config.Workspace)
+		if err != nil {
+			return nil, err
+		}
+		prepared.cleanup = func() { os.RemoveAll(dir) }
+		if config.Cwd == "" || !filepath.IsAbs(config.Cwd) {
+			prepared.cmd.Dir = filepath.Join(dir, config.Cwd)
+		}
+		env = append(env, workspaceVariable+"="+dir)
+	}
+
+	if len(env) > 0 {
+		prepared.cmd.Env = append(os.Environ(), env...)
+	}
+	return prepared, nil
+}
+
+// commandEnv returns the configured environment variables as sorted KEY=value pairs
+func (e *UnifiedTestExecutor) commandEnv(config *CommandTest, hookCtx map[string]interface{}, prepared *preparedCommand) ([]string, error) {
+	names := make([]string, 0, len(config.Env))
+	for name := range config.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	env := make([]string, 0, len(names))
+	for _, name := range names {
+		value := config.Env[name]
+		prepared.addSecrets(e.secretReferences(hookCtx, value)...)
+		if missing := e.resolveReferences(hookCtx, &value); len(missing) > 0 {
+			return nil, fmt.Errorf("command env: variable %s is not set", strings.Join(missing, ", "))
+		}
+		if isSecretName(name) {
+			prepared.addSecrets(value)
+		}
+		env = append(env, name+"="+value)
+	}
+	return env, nil
+}
+
+// secretReferences returns the values of the secret environment variables referenced in s
+func (e *UnifiedTestExecutor) secretReferences(hookCtx map[string]interface{}, s string) []string {
+	provider, ok := e.variableInjector.(SecretVariableProvider)
+	if !ok || !strings.Contains(s, "{{") {
+		return nil
+	}
+	keys, err := provider.GetActiveEnvironmentSecretKeys()
+	if err != nil {
+		return nil
+	}
+	secret := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		secret[key] = true
+	}
+
+	var values []string
+	for _, match := range referencePattern.FindAllStringSubmatch(s, -1) {
+		if !secret[match[1]] {
+			continue
+		}
+		value := match[0]
+		if missing := e.resolveReferences(hookCtx, &value); len(missing) == 0 {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// shellCommand runs script with the platform shell. Args are passed as positional parameters ($1, $2, ...).
+func shellCommand(script string, args []string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", append([]string{"/C", script}, args...)...)
+	}
+	return exec.Command("sh", append([]string{"-c", script, "sh"}, args...)...)
+}
+
+// createWorkspace creates a temporary directory populated with the workspace's fixture files
+func createWorkspace(workspace *CommandWorkspace) (string, error) {
+	dir, err := os.MkdirTemp("", "command-workspace-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create workspace: %v", err)
+	}
+
+	for _, file := range workspace.Files {
+		if err := writeWorkspaceFile(dir, file); err != nil {
+			os.RemoveAll(dir)
+			return "", fmt.Errorf("workspace file %s: %v", file.Path, err)
+		}
+	}
+	return dir, nil
+}
+
+// writeWorkspaceFile writes a fixture file from inline content, base64 content or a source file
+func writeWorkspaceFile(dir string, file WorkspaceFile) error {
+	if !filepath.IsLocal(file.Path) {
+		return fmt.Errorf("path must be relative to the workspace")
+	}
+
+	var data []byte
+	var err error
+	switch {
+	case file.Source != "":
+		data, err = os.ReadFile(file.Source)
+	case file.ContentBase64 != "":
+		data, err = base64.StdEncoding.DecodeString(file.ContentBase64)
+	default:
+		data = []byte(file.Content)
+	}
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, file.Path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	mode := os.FileMode(0644)
+	if file.Executable {
+		mode = 0755
+	}
+	return os.WriteFile(path, data, mode)
+}
+
+// addSecrets records values to mask
+func (p *preparedCommand) addSecrets(values ...string) {
+	for _, value := range values {
+		if value != "" {
+			p.secrets = append(p.secrets, value)
+		}
+	}
+}
+
+// mask replaces secret values in command output
+func (p *preparedCommand) mask(s string) string {
+	for _, secret := range p.secrets {
+		s = strings.ReplaceAll(s, secret, secretMask)
+	}
+	return s
+}
+
+// maskResult masks secret values in the error, failures and assertion records of a result
+func (p *preparedCommand) maskResult(result *TestResult) {
+	if len(p.secrets) == 0 {
+		return
+	}
+	result.Error = p.mask(result.Error)
+	for i, failure := range result.Failures {
+		result.Failures[i] = p.mask(failure)
+	}
+	for i := range result.Assertions {
+		record := &result.Assertions[i]
+		record.Message = p.mask(record.Message)
+		if actual, ok := record.Actual.(string); ok {
+			record.Actual = p.mask(actual)
+		}
+	}
+}
+
+// request summarizes the command for the test result, with secret environment values masked
+func (p *preparedCommand) request(config *CommandTest) map[string]interface{} {
+	args := make([]string, len(config.Args))
+	for i, arg := range config.Args {
+		args[i] = p.mask(arg)
+	}
+	request := map[string]interface{}{
+		"cmd":  p.mask(config.Cmd),
+		"args": args,
+	}
+	if p.cmd.Dir != "" {
+		request["cwd"] = p.cmd.Dir
+	}
+	if config.Shell {
+		request["shell"] = true
+	}
+	if p.stdin != "" {
+		request["stdin"] = p.mask(p.stdin)
+	}
+	if len(p.env) > 0 {
+		env := make(map[string]string, len(p.env))
+		for _, pair := range p.env {
+			name, value, _ := strings.Cut(pair, "=")
+			env[name] = p.mask(value)
+		}
+		request["env"] = env
+	}
+	return request
+}
+
+// isSecretName reports whether a variable name suggests its value is a credential
+func isSecretName(name string) bool {
+	name = strings.ToLower(name)
+	for _, sensitive := range harSensitiveNames {
+		if strings.Contains(name, sensitive) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package testcase
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// secretEnvironmentInjector is a fakeEnvironmentInjector that marks some variables as secret
+type secretEnvironmentInjector struct {
+	fakeEnvironmentInjector
+	secretKeys []string
+}
+
+func (f *secretEnvironmentInjector) GetActiveEnvironmentSecretKeys() ([]string, error) {
+	return f.secretKeys, nil
+}
+
+// InjectCommandVariables fills {{NAME}} in the command line from the active environment, like
+// the environment variable injector
+func (f *secretEnvironmentInjector) InjectCommandVariables(config *CommandTest) error {
+	for name, value := range f.vars[f.envID] {
+		config.Cmd = strings.ReplaceAll(config.Cmd, "{{"+name+"}}", value)
+		for i, arg := range config.Args {
+			config.Args[i] = strings.ReplaceAll(arg, "{{"+name+"}}", value)
+		}
+	}
+	return nil
+}
+
+// TestCommand_EnvStdinAndShell tests environment variables, standard input, shell mode and stderr assertions
+func TestCommand_EnvStdinAndShell(t *testing.T) {
+	injector := &secretEnvironmentInjector{
+		fakeEnvironmentInjector: fakeEnvironmentInjector{
+			envID: "dev",
+			vars:  map[string]map[string]string{"dev": {"API_URL": "https://api.dev", "API_TOKEN": "tok-secret-1"}},
+		},
+		secretKeys: []string{"API_TOKEN"},
+	}
+	executor := NewExecutorWithInjector("", nil, nil, nil, injector)
+
+	result := executor.Execute(&TestCase{
+		ID:   "command-env",
+		Type: "command",
+		Command: &CommandTest{
+			Cmd:   `echo "url=$APP_URL token=$APP_TOKEN level=$LOG_LEVEL arg=$1"; read line; echo "stdin=$line"; echo "warning: deprecated flag" >&2`,
+			Args:  []string{"first"},
+			Shell: true,
+			Env:   map[string]string{"APP_URL": "{{API_URL}}", "APP_TOKEN": "Bearer {{API_TOKEN}}", "LOG_LEVEL": "debug"},
+			Stdin: "{\"user\": \"alice\"}\n",
+		},
+		Assertions: []Assertion{
+			{Type: "stdout_contains", Expected: "url=https://api.dev token=Bearer tok-secret-1 level=debug arg=first"},
+			{Type: "stdout_contains", Expected: `stdin={"user": "alice"}`},
+			{Type: "stderr_contains", Expected: "deprecated"},
+			{Type: "stderr", Operator: "not_contains", Expected: "error"},
+			{Type: "expr", Expected: `stderr.startsWith("warning")`},
+		},
+	})
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+
+	// Secret values are masked wherever the command's inputs and output are recorded
+	assert.Equal(t, map[string]string{"APP_URL": "https://api.dev", "APP_TOKEN": "Bearer ******", "LOG_LEVEL": "debug"}, result.Request["env"])
+	assert.Contains(t, result.Response["stdout"], "token=Bearer ****** level=debug")
+	assert.NotContains(t, result.Response["stdout"], "tok-secret-1")
+	assert.Equal(t, true, result.Request["shell"])
+
+	result = executor.Execute(&TestCase{
+		ID:         "command-secret-failure",
+		Type:       "command",
+		Command:    &CommandTest{Cmd: "printenv", Args: []string{"APP_TOKEN"}, Env: map[string]string{"APP_TOKEN": "{{API_TOKEN}}"}},
+		Assertions: []Assertion{{Type: "stdout", Expected: "something else"}},
+	})
+	assert.Equal(t, "failed", result.Status)
+	require.Len(t, result.Assertions, 1)
+	assert.Equal(t, "******\n", result.Assertions[0].Actual)
+	assert.NotContains(t, strings.Join(result.Failures, "\n"), "tok-secret-1")
+
+	// Secrets in the command line are masked in the request, failures and error
+	result = executor.Execute(&TestCase{
+		ID:         "command-secret-args",
+		Type:       "command",
+		Command:    &CommandTest{Cmd: `echo "login rejected for $1" >&2; exit 1`, Args: []string{"{{API_TOKEN}}"}, Shell: true},
+		Assertions: []Assertion{{Type: "stderr", Operator: "equals", Expected: "ok"}},
+	})
+	assert.Equal(t, "failed", result.Status)
+	assert.Equal(t, []string{"******"}, result.Request["args"])
+	assert.Equal(t, "login rejected for ******\n", result.Response["stderr"])
+	assert.Contains(t, strings.Join(result.Failures, "\n"), "login rejected for ******")
+	assert.NotContains(t, strings.Join(result.Failures, "\n"), "tok-secret-1")
+
+	result = executor.Execute(&TestCase{
+		ID:      "command-secret-error",
+		Type:    "command",
+		Command: &CommandTest{Cmd: "/nonexistent/{{API_TOKEN}}/deploy"},
+	})
+	assert.Equal(t, "error", result.Status)
+	assert.Contains(t, result.Error, "/nonexistent/******/deploy")
+	assert.NotContains(t, result.Error, "tok-secret-1")
+	assert.Equal(t, "/nonexistent/******/deploy", result.Request["cmd"])
+
+	result = executor.Execute(&TestCase{
+		ID:      "command-missing-variable",
+		Type:    "command",
+		Command: &CommandTest{Cmd: "true", Env: map[string]string{"APP_TOKEN": "{{MISSING_TOKEN}}"}},
+	})
+	assert.Equal(t, "error", result.Status)
+	assert.Equal(t, "command env: variable MISSING_TOKEN is not set", result.Error)
+}
+
+// TestCommand_Workspace tests that fixture files are written to a temporary workspace that is removed afterwards
+func TestCommand_Workspace(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "settings.json")
+	require.NoError(t, os.WriteFile(source, []byte(`{"retries": 3}`), 0644))
+
+	executor := NewExecutor("")
+	result := executor.Execute(&TestCase{
+		ID:   "command-workspace",
+		Type: "command",
+		Command: &CommandTest{
+			Cmd:   `./bin/run.sh && cat conf/app.yaml conf/settings.json data.bin && pwd && echo "$WORKSPACE"`,
+			Shell: true,
+			Workspace: &CommandWorkspace{Files: []WorkspaceFile{
+				{Path: "bin/run.sh", Content: "#!/bin/sh\necho running\n", Executable: true},
+				{Path: "conf/app.yaml", Content: "name: demo\n"},
+				{Path: "conf/settings.json", Source: source},
+				{Path: "data.bin", ContentBase64: base64.StdEncoding.EncodeToString([]byte("binary\n"))},
+			}},
+		},
+		Assertions: []Assertion{
+			{Type: "exit_code", Expected: 0},
+			{Type: "stdout_contains", Expected: "running\nname: demo\n{\"retries\": 3}binary\n"},
+		},
+	})
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+
+	// The command runs in the workspace, which is gone after the test
+	lines := strings.Split(strings.TrimSpace(result.Response["stdout"].(string)), "\n")
+	workspace := lines[len(lines)-1]
+	assert.Equal(t, workspace, lines[len(lines)-2])
+	assert.Equal(t, workspace, result.Request["cwd"])
+	_, err := os.Stat(workspace)
+	assert.True(t, os.IsNotExist(err), "workspace %s should be removed", workspace)
+
+	// Fixture paths cannot escape the workspace
+	result = executor.Execute(&TestCase{
+		ID:   "command-workspace-escape",
+		Type: "command",
+		Command: &CommandTest{
+			Cmd:       "true",
+			Workspace: &CommandWorkspace{Files: []WorkspaceFile{{Path: "../outside.txt", Content: "x"}}},
+		},
+	})
+	assert.Equal(t, "error", result.Status)
+	assert.Equal(t, "workspace file ../outside.txt: path must be relative to the workspace", result.Error)
+}
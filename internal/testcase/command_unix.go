@@ -0,0 +1,21 @@
+//go:build !windows
+
+package testcase
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// startInProcessGroup makes the command the leader of a new process group, so that the processes
+// it starts, such as those of a shell script, can be killed with it
+func startInProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killProcessGroup kills a started command and the processes in its group
+func killProcessGroup(cmd *exec.Cmd) {
+	if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL); err != nil {
+		cmd.Process.Kill()
+	}
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package testcase
+
+import "os/exec"
+
+// startInProcessGroup is a no-op on Windows; child processes are not tracked
+func startInProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills a started command
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
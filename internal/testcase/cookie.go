@@ -0,0 +1,95 @@
+package testcase
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+)
+
+type cookieJarKey struct{}
+
+// NewCookieJar creates an empty cookie jar for a session
+func NewCookieJar() http.CookieJar {
+	jar, _ := cookiejar.New(nil)
+	return jar
+}
+
+// WithCookieJar returns a context whose HTTP requests send and store cookies in jar.
+// Tests executed under it share the session instead of starting their own.
+func WithCookieJar(ctx context.Context, jar http.CookieJar) context.Context {
+	return context.WithValue(ctx, cookieJarKey{}, jar)
+}
+
+// cookieJarFrom returns the cookie jar of ctx, or nil if requests do not keep a session
+func cookieJarFrom(ctx context.Context) http.CookieJar {
+	jar, _ := ctx.Value(cookieJarKey{}).(http.CookieJar)
+	return jar
+}
+
+// executionTransport wraps base with the per-execution cookie session and HAR recording
+func executionTransport(base http.RoundTripper) http.RoundTripper {
+	return &cookieTransport{base: &harTransport{base: base}}
+}
+
+// cookieTransport sends and stores cookies in the jar carried by the request context.
+// Handling cookies per round trip keeps the session across redirects.
+type cookieTransport struct {
+	base http.RoundTripper
+}
+
+func (t *cookieTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	jar := cookieJarFrom(req.Context())
+	if jar == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	if cookies := jar.Cookies(req.URL); len(cookies) > 0 {
+		req = req.Clone(req.Context())
+		for _, cookie := range cookies {
+			// Cookies set explicitly in the request headers take precedence
+			if _, err := req.Cookie(cookie.Name); err == nil {
+				continue
+			}
+			req.AddCookie(cookie)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if cookies := resp.Cookies(); len(cookies) > 0 {
+		jar.SetCookies(req.URL, cookies)
+	}
+	return resp, nil
+}
+
+// sessionCookies returns the cookies the session holds for the response's URL,
+// overlaid with those set by the response itself
+func sessionCookies(ctx context.Context, resp *http.Response) []*http.Cookie {
+	cookies := resp.Cookies()
+	jar := cookieJarFrom(ctx)
+	if jar == nil || resp.Request == nil {
+		return cookies
+	}
+
+	set := make(map[string]bool, len(cookies))
+	for _, cookie := range cookies {
+		set[cookie.Name] = true
+	}
+	for _, cookie := range jar.Cookies(resp.Request.URL) {
+		if !set[cookie.Name] {
+			cookies = append(cookies, cookie)
+		}
+	}
+	return cookies
+}
+
+// cookieValues maps cookie names to values for extraction and expressions
+func cookieValues(cookies []*http.Cookie) map[string]interface{} {
+	values := make(map[string]interface{}, len(cookies))
+	for _, cookie := range cookies {
+		values[cookie.Name] = cookie.Value
+	}
+	return values
+}
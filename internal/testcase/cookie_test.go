@@ -0,0 +1,124 @@
+package testcase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sessionServer is a cookie-session web app: login sets a session, other pages require it
+func sessionServer(logouts *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "sid-42", Path: "/", HttpOnly: true})
+			w.Write([]byte(`{"ok": true}`))
+			return
+		case "/sso":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "sid-sso", Path: "/"})
+			http.Redirect(w, r, "/profile", http.StatusFound)
+			return
+		}
+
+		cookie, err := r.Cookie("session")
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/logout":
+			atomic.AddInt32(logouts, 1)
+			http.SetCookie(w, &http.Cookie{Name: "session", Path: "/", MaxAge: -1})
+		default:
+			w.Write([]byte(`{"session": "` + cookie.Value + `"}`))
+		}
+	}))
+}
+
+// TestCookie_SessionAcrossHooks tests that a login in a setup hook carries its session into the test and teardown
+func TestCookie_SessionAcrossHooks(t *testing.T) {
+	var logouts int32
+	server := sessionServer(&logouts)
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	result := executor.Execute(&TestCase{
+		ID:   "cookie-session",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "GET", Path: "/profile"},
+		SetupHooks: []Hook{
+			{Type: "http", Name: "login", HTTP: &HTTPTest{Method: "POST", Path: "/login"}, Extract: map[string]string{"sid": "$.cookies.session"}},
+		},
+		TeardownHooks: []Hook{
+			{Type: "http", Name: "logout", HTTP: &HTTPTest{Method: "POST", Path: "/logout"}},
+		},
+		Assertions: []Assertion{
+			{Type: "status_code", Expected: 200},
+			{Type: "json_path", Path: "$.session", Expected: "sid-42"},
+			{Type: "cookie", Path: "session", Expected: "sid-42"},
+			{Type: "expr", Expected: `vars.sid == cookies.session`},
+		},
+	})
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+	assert.Equal(t, map[string]interface{}{"session": "sid-42"}, result.Response["cookies"])
+	assert.Equal(t, int32(1), atomic.LoadInt32(&logouts))
+
+	// Attributes are only known for cookies set by the response itself
+	result = executor.Execute(&TestCase{
+		ID:   "cookie-attributes",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "GET", Path: "/profile"},
+		SetupHooks: []Hook{
+			{Type: "http", Name: "login", HTTP: &HTTPTest{Method: "POST", Path: "/login"}},
+		},
+		Assertions: []Assertion{{Type: "cookie", Path: "session.httpOnly", Expected: true}},
+	})
+	assert.Equal(t, "failed", result.Status)
+
+	// Cookies set before a redirect are sent to the redirect target
+	result = executor.Execute(&TestCase{
+		ID:         "cookie-redirect",
+		Type:       "http",
+		HTTP:       &HTTPTest{Method: "GET", Path: "/sso"},
+		Assertions: []Assertion{{Type: "json_path", Path: "$.session", Expected: "sid-sso"}},
+	})
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+}
+
+// TestCookie_SessionScope tests that each execution starts a new session unless the caller shares one
+func TestCookie_SessionScope(t *testing.T) {
+	var logouts int32
+	server := sessionServer(&logouts)
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	login := &TestCase{ID: "login", Type: "http", HTTP: &HTTPTest{Method: "POST", Path: "/login"}}
+	profile := &TestCase{
+		ID:         "profile",
+		Type:       "http",
+		HTTP:       &HTTPTest{Method: "GET", Path: "/profile"},
+		Assertions: []Assertion{{Type: "status_code", Expected: 200}},
+	}
+
+	executor.Execute(login)
+	assert.Equal(t, "failed", executor.Execute(profile).Status)
+
+	// A group run can share one session between its tests
+	ctx := WithCookieJar(context.Background(), NewCookieJar())
+	executor.ExecuteContext(ctx, login)
+	result := executor.ExecuteContext(ctx, profile)
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+
+	// Cookies set explicitly in the request headers take precedence over the session
+	result = executor.ExecuteContext(ctx, &TestCase{
+		ID:         "explicit-cookie",
+		Type:       "http",
+		HTTP:       &HTTPTest{Method: "GET", Path: "/profile", Headers: map[string]string{"Cookie": "session=manual"}},
+		Assertions: []Assertion{{Type: "json_path", Path: "$.session", Expected: "manual"}},
+	})
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+}
@@ -2,25 +2,41 @@
 package testcase
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os/exec"
-	"strings"
+	"sync"
 	"time"
 
 	"test-management-service/internal/models"
 )
 
+const (
+	// defaultTestTimeout applies to tests without a timeout, matching the stored default
+	defaultTestTimeout = 300 * time.Second
+	// teardownTimeout bounds teardown hooks, which still run after a test is interrupted
+	teardownTimeout = 30 * time.Second
+)
+
 // VariableInjector interface for injecting environment variables
 type VariableInjector interface {
 	InjectHTTPVariables(config *HTTPTest) error
 	InjectCommandVariables(config *CommandTest) error
+	GetActiveEnvironmentVariables() (map[string]string, error)
+	GetActiveEnvironmentID() (string, error)
 }
 
-// UnifiedTestExecutor executes test cases of all types (http, command, workflow, etc.)
+// SecretVariableProvider is implemented by variable injectors that know which variables of the
+// active environment are secret, so that their values can be masked in command results
+type SecretVariableProvider interface {
+	GetActiveEnvironmentSecretKeys() ([]string, error)
+}
+
+// UnifiedTestExecutor executes test cases of all types (http, command, workflow, grpc, websocket, database, performance, security, etc.)
 type UnifiedTestExecutor struct {
 	baseURL          string
 	client           *http.Client
@@ -28,17 +44,22 @@ type UnifiedTestExecutor struct {
 	testCaseRepo     TestCaseRepository // Repository for test case data
 	workflowRepo     WorkflowRepository // Repository for workflow data
 	variableInjector VariableInjector   // Injector for environment variables
+	schemaRepo       SchemaRepository   // Repository for stored JSON schemas
+	snapshotRepo     SnapshotRepository // Repository for response snapshots
+	tokenCache       *oauthTokenCache   // OAuth2 access tokens shared across tests
+	tlsConfig        *TLSConfig         // TLS client settings of the target group
+	tlsClients       *sync.Map          // HTTP clients keyed by their resolved TLS settings
 }
 
 // WorkflowExecutor interface for workflow execution
 type WorkflowExecutor interface {
-	Execute(workflowID string, workflowDef interface{}) (*WorkflowResult, error)
+	ExecuteContext(ctx context.Context, workflowID string, workflowDef interface{}) (*WorkflowResult, error)
 }
 
 // WorkflowResult represents the result of a workflow execution
 type WorkflowResult struct {
 	RunID            string
-	Status           string // success, failed, cancelled
+	Status           string // success, failed, timeout, cancelled
 	StartTime        time.Time
 	EndTime          time.Time
 	Duration         int
@@ -71,6 +92,17 @@ type WorkflowRepository interface {
 	GetWorkflow(workflowID string) (*models.Workflow, error)
 }
 
+// SchemaRepository provides access to stored JSON schemas
+type SchemaRepository interface {
+	GetSchema(schemaID string) (*models.JSONSchema, error)
+}
+
+// SnapshotRepository provides access to stored response snapshots
+type SnapshotRepository interface {
+	GetSnapshot(testID, name string) (*models.Snapshot, error)
+	SaveSnapshot(snapshot *models.Snapshot) error
+}
+
 // NewUnifiedTestExecutor creates a new unified test executor
 func NewUnifiedTestExecutor(baseURL string, workflowExecutor WorkflowExecutor, testCaseRepo TestCaseRepository, workflowRepo WorkflowRepository) *UnifiedTestExecutor {
 	return &UnifiedTestExecutor{
@@ -78,9 +110,9 @@ func NewUnifiedTestExecutor(baseURL string, workflowExecutor WorkflowExecutor, t
 		workflowExecutor: workflowExecutor,
 		testCaseRepo:     testCaseRepo,
 		workflowRepo:     workflowRepo,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		tokenCache:       newOAuthTokenCache(),
+		tlsClients:       &sync.Map{},
+		client:           &http.Client{Transport: executionTransport(http.DefaultTransport)},
 	}
 }
 
@@ -92,9 +124,9 @@ func NewExecutorWithInjector(baseURL string, workflowExecutor WorkflowExecutor,
 		testCaseRepo:     testCaseRepo,
 		workflowRepo:     workflowRepo,
 		variableInjector: variableInjector,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		tokenCache:       newOAuthTokenCache(),
+		tlsClients:       &sync.Map{},
+		client:           &http.Client{Transport: executionTransport(http.DefaultTransport)},
 	}
 }
 
@@ -103,8 +135,32 @@ func NewExecutor(baseURL string) *UnifiedTestExecutor {
 	return NewUnifiedTestExecutor(baseURL, nil, nil, nil)
 }
 
+// SetSchemaRepository sets the repository used to resolve stored schemas in json_schema assertions
+func (e *UnifiedTestExecutor) SetSchemaRepository(schemaRepo SchemaRepository) {
+	e.schemaRepo = schemaRepo
+}
+
+// SetSnapshotRepository sets the repository used to load and record snapshots in snapshot assertions
+func (e *UnifiedTestExecutor) SetSnapshotRepository(snapshotRepo SnapshotRepository) {
+	e.snapshotRepo = snapshotRepo
+}
+
+// WithBaseURL returns a copy of the executor that targets a different base URL,
+// keeping its repositories and variable injector
+func (e *UnifiedTestExecutor) WithBaseURL(baseURL string) *UnifiedTestExecutor {
+	clone := *e
+	clone.baseURL = baseURL
+	return &clone
+}
+
 // Execute runs a test case with lifecycle hooks (unified entry point)
 func (e *UnifiedTestExecutor) Execute(tc *TestCase) *TestResult {
+	return e.ExecuteContext(context.Background(), tc)
+}
+
+// ExecuteContext runs a test case with lifecycle hooks under ctx. In-flight work stops when
+// ctx is cancelled or the test's timeout elapses, and the result is marked timeout or cancelled.
+func (e *UnifiedTestExecutor) ExecuteContext(ctx context.Context, tc *TestCase) *TestResult {
 	result := &TestResult{
 		TestID:    tc.ID,
 		Name:      tc.Name,
@@ -112,50 +168,117 @@ func (e *UnifiedTestExecutor) Execute(tc *TestCase) *TestResult {
 		Status:    "passed",
 	}
 
-	// Context for storing hook responses
-	ctx := make(map[string]interface{})
+	timeout := defaultTestTimeout
+	if tc.Timeout > 0 {
+		timeout = time.Duration(tc.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeoutCause(ctx, timeout, fmt.Errorf("test timed out after %v", timeout))
+	defer cancel()
+
+	// Record HTTP exchanges, including those of tests nested in workflow steps
+	if recorder := harRecorderFrom(ctx); recorder != nil {
+		ctx = withHARComment(ctx, tc.ID)
+	} else {
+		recorder = &harRecorder{}
+		ctx = withHARRecorder(ctx, recorder)
+		defer func() { result.HAR = recorder.har() }()
+	}
+
+	// Share one cookie session between hooks and the test, unless the caller provides one
+	if cookieJarFrom(ctx) == nil {
+		ctx = WithCookieJar(ctx, NewCookieJar())
+	}
+
+	// Context for storing hook responses, starting with the variables extracted earlier in the run
+	hookCtx := make(map[string]interface{})
+	if runVars := runVariablesFrom(ctx); runVars != nil {
+		for name, value := range runVars.Values() {
+			hookCtx[name] = value
+		}
+	}
 
 	defer func() {
+		markInterrupted(ctx, result)
 		result.EndTime = time.Now()
 		result.Duration = result.EndTime.Sub(result.StartTime)
 
-		// Run teardown hooks (always execute, even on failure)
-		e.executeTeardownHooks(tc, result, ctx)
+		// Run teardown hooks (always execute, even on failure or cancellation)
+		teardownCtx, cancelTeardown := context.WithTimeout(context.WithoutCancel(ctx), teardownTimeout)
+		defer cancelTeardown()
+		e.executeTeardownHooks(teardownCtx, tc, result, hookCtx)
 	}()
 
 	// Run setup hooks
-	if !e.executeSetupHooks(tc, result, ctx) {
+	if !e.executeSetupHooks(ctx, tc, result, hookCtx) {
 		// Setup failed, skip test execution
 		return result
 	}
 
 	// Execute the main test
+	testCtx := withHARComment(ctx, "test")
 	switch tc.Type {
 	case "http":
-		e.executeHTTP(tc, result)
+		e.executeHTTP(testCtx, tc, result, hookCtx)
 	case "command":
-		e.executeCommand(tc, result)
+		e.executeCommand(testCtx, tc, result, hookCtx)
 	case "workflow":
-		e.executeWorkflowTest(tc, result)
+		e.executeWorkflowTest(testCtx, tc, result)
+	case "grpc":
+		e.executeGRPC(testCtx, tc, result)
+	case "websocket":
+		e.executeWebSocket(testCtx, tc, result)
+	case "database":
+		e.executeDatabase(testCtx, tc, result)
+	case "performance":
+		e.executePerformance(testCtx, tc, result, hookCtx)
+	case "security":
+		e.executeSecurity(testCtx, tc, result)
 	default:
 		result.Status = "error"
 		result.Error = fmt.Sprintf("unsupported test type: %s", tc.Type)
 	}
 
+	// Save extracted values for teardown hooks and later tests
+	extractVariables(ctx, tc, result, hookCtx)
+
 	return result
 }
 
+// markInterrupted marks a result whose context ended before the test finished:
+// timeout when a deadline passed, cancelled otherwise
+func markInterrupted(ctx context.Context, result *TestResult) {
+	err := ctx.Err()
+	if err == nil {
+		return
+	}
+
+	cause := context.Cause(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		result.Status = "timeout"
+		if cause == err {
+			cause = errors.New("test timed out")
+		}
+	} else {
+		result.Status = "cancelled"
+		if cause == err {
+			cause = errors.New("test cancelled")
+		}
+	}
+	result.Error = cause.Error()
+}
+
 // executeHTTP executes an HTTP test
-func (e *UnifiedTestExecutor) executeHTTP(tc *TestCase, result *TestResult) {
+func (e *UnifiedTestExecutor) executeHTTP(ctx context.Context, tc *TestCase, result *TestResult, hookCtx map[string]interface{}) {
 	if tc.HTTP == nil {
 		result.Status = "error"
 		result.Error = "HTTP configuration missing"
 		return
 	}
 
-	// Inject environment variables if variableInjector is available
+	// Resolve variables saved by hooks and earlier tests, then environment variables
+	config := templateHTTP(tc.HTTP, hookCtx)
 	if e.variableInjector != nil {
-		if err := e.variableInjector.InjectHTTPVariables(tc.HTTP); err != nil {
+		if err := e.variableInjector.InjectHTTPVariables(config); err != nil {
 			result.Status = "error"
 			result.Error = fmt.Sprintf("failed to inject variables: %v", err)
 			return
@@ -163,20 +286,24 @@ func (e *UnifiedTestExecutor) executeHTTP(tc *TestCase, result *TestResult) {
 	}
 
 	// Prepare request body
-	var bodyReader io.Reader
-	if tc.HTTP.Body != nil {
-		bodyBytes, err := json.Marshal(tc.HTTP.Body)
-		if err != nil {
-			result.Status = "error"
-			result.Error = fmt.Sprintf("failed to marshal body: %v", err)
-			return
-		}
-		bodyReader = bytes.NewReader(bodyBytes)
+	requestBody, err := encodeHTTPBody(config)
+	if err != nil {
+		result.Status = "error"
+		result.Error = err.Error()
+		return
+	}
+
+	// Resolve authentication credentials
+	auth, err := e.resolveAuth(config.Auth, hookCtx)
+	if err != nil {
+		result.Status = "error"
+		result.Error = err.Error()
+		return
 	}
 
 	// Build URL
-	url := e.baseURL + tc.HTTP.Path
-	req, err := http.NewRequest(tc.HTTP.Method, url, bodyReader)
+	url := e.baseURL + config.Path
+	req, err := http.NewRequestWithContext(ctx, config.Method, url, requestBody.reader())
 	if err != nil {
 		result.Status = "error"
 		result.Error = fmt.Sprintf("failed to create request: %v", err)
@@ -184,20 +311,36 @@ func (e *UnifiedTestExecutor) executeHTTP(tc *TestCase, result *TestResult) {
 	}
 
 	// Set headers
-	for k, v := range tc.HTTP.Headers {
-		req.Header.Set(k, v)
+	setRequestHeaders(req, config.Headers, requestBody)
+	if err := e.applyAuth(req, auth, requestBody); err != nil {
+		result.Status = "error"
+		result.Error = err.Error()
+		return
 	}
 
 	// Store request info
 	result.Request = map[string]interface{}{
-		"method":  tc.HTTP.Method,
+		"method":  config.Method,
 		"url":     url,
-		"headers": tc.HTTP.Headers,
-		"body":    tc.HTTP.Body,
+		"headers": config.Headers,
+		"body":    requestBodySummary(config, requestBody),
+	}
+	if config.BodyType != "" {
+		result.Request["bodyType"] = config.BodyType
+	}
+	if auth != nil {
+		result.Request["auth"] = auth.Type
 	}
 
 	// Execute request
-	resp, err := e.client.Do(req)
+	client, err := e.httpClient()
+	if err != nil {
+		result.Status = "error"
+		result.Error = err.Error()
+		return
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
 	if err != nil {
 		result.Status = "error"
 		result.Error = fmt.Sprintf("request failed: %v", err)
@@ -207,61 +350,88 @@ func (e *UnifiedTestExecutor) executeHTTP(tc *TestCase, result *TestResult) {
 
 	// Read response body
 	bodyBytes, _ := io.ReadAll(resp.Body)
-	var responseBody map[string]interface{}
-	json.Unmarshal(bodyBytes, &responseBody)
+	elapsed := time.Since(start)
+	responseBody := decodeResponseBody(config.ResponseType, resp.Header.Get("Content-Type"), bodyBytes)
+	cookies := sessionCookies(ctx, resp)
 
 	result.Response = map[string]interface{}{
-		"statusCode": resp.StatusCode,
-		"headers":    resp.Header,
-		"body":       responseBody,
-		"bodyRaw":    string(bodyBytes),
+		"statusCode":     resp.StatusCode,
+		"headers":        resp.Header,
+		"cookies":        cookieValues(cookies),
+		"responseTimeMs": elapsedMillis(elapsed),
+	}
+	for k, v := range responseBody.responseFields() {
+		result.Response[k] = v
 	}
 
 	// Run assertions
-	e.runHTTPAssertions(tc.Assertions, resp.StatusCode, responseBody, result)
+	activation := e.exprActivation(tc.Assertions, hookCtx, map[string]interface{}{
+		"status":   resp.StatusCode,
+		"headers":  exprHeaders(resp.Header),
+		"cookies":  cookieValues(cookies),
+		"body":     responseBody.snapshotSubject(),
+		"duration": elapsedMillis(elapsed),
+	})
+	e.runHTTPAssertions(tc.Assertions, resp, cookies, responseBody, elapsed, activation, result)
 }
 
 // executeCommand executes a command test
-func (e *UnifiedTestExecutor) executeCommand(tc *TestCase, result *TestResult) {
+func (e *UnifiedTestExecutor) executeCommand(ctx context.Context, tc *TestCase, result *TestResult, hookCtx map[string]interface{}) {
 	if tc.Command == nil {
 		result.Status = "error"
 		result.Error = "Command configuration missing"
 		return
 	}
 
-	// Inject environment variables if variableInjector is available
+	// Resolve variables saved by hooks and earlier tests, then environment variables
+	command := templateCommand(tc.Command, hookCtx)
 	if e.variableInjector != nil {
-		if err := e.variableInjector.InjectCommandVariables(tc.Command); err != nil {
+		if err := e.variableInjector.InjectCommandVariables(command); err != nil {
 			result.Status = "error"
 			result.Error = fmt.Sprintf("failed to inject variables: %v", err)
 			return
 		}
 	}
 
-	cmd := exec.Command(tc.Command.Cmd, tc.Command.Args...)
-	if tc.Command.Cwd != "" {
-		cmd.Dir = tc.Command.Cwd
+	prepared, err := e.prepareCommand(command, hookCtx)
+	if err != nil {
+		result.Status = "error"
+		result.Error = err.Error()
+		return
 	}
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	// Secret values referenced in the command line are masked like those in its environment
+	for _, part := range append([]string{tc.Command.Cmd}, tc.Command.Args...) {
+		prepared.addSecrets(e.secretReferences(hookCtx, part)...)
+	}
+	cmd := prepared.cmd
+	result.Request = prepared.request(command)
+	defer prepared.maskResult(result)
 
 	// Set timeout
 	timeout := 60 * time.Second
-	if tc.Command.Timeout > 0 {
-		timeout = time.Duration(tc.Command.Timeout) * time.Second
+	if command.Timeout > 0 {
+		timeout = time.Duration(command.Timeout) * time.Second
 	}
 
+	// Start synchronously so the process exists before it can be killed below
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		prepared.cleanup()
+		result.Status = "error"
+		result.Error = fmt.Sprintf("command failed: %v", err)
+		return
+	}
 	done := make(chan error, 1)
 	go func() {
-		done <- cmd.Run()
+		err := cmd.Wait()
+		prepared.cleanup()
+		done <- err
 	}()
 
 	select {
 	case err := <-done:
 		exitCode := 0
-		if err != nil {
+		if err != nil && !errors.Is(err, exec.ErrWaitDelay) {
 			if exitErr, ok := err.(*exec.ExitError); ok {
 				exitCode = exitErr.ExitCode()
 			} else {
@@ -271,24 +441,40 @@ func (e *UnifiedTestExecutor) executeCommand(tc *TestCase, result *TestResult) {
 			}
 		}
 
+		// Secret values are masked in the recorded output
+		stdout, stderr := prepared.stdout.String(), prepared.stderr.String()
 		result.Response = map[string]interface{}{
 			"exitCode": exitCode,
-			"stdout":   stdout.String(),
-			"stderr":   stderr.String(),
+			"stdout":   prepared.mask(stdout),
+			"stderr":   prepared.mask(stderr),
 		}
 
-		// Run assertions
-		e.runCommandAssertions(tc.Assertions, exitCode, stdout.String(), result)
+		// Run assertions; body is stdout parsed as JSON when possible
+		var body interface{}
+		json.Unmarshal([]byte(stdout), &body)
+		activation := e.exprActivation(tc.Assertions, hookCtx, map[string]interface{}{
+			"body":     body,
+			"duration": elapsedMillis(time.Since(start)),
+			"stdout":   stdout,
+			"stderr":   stderr,
+			"exitCode": exitCode,
+		})
+		e.runCommandAssertions(tc.Assertions, exitCode, stdout, stderr, activation, result)
 
 	case <-time.After(timeout):
-		cmd.Process.Kill()
-		result.Status = "failed"
-		result.Failures = append(result.Failures, fmt.Sprintf("command timeout after %v", timeout))
+		killProcessGroup(cmd)
+		<-done
+		result.Status = "timeout"
+		result.Error = fmt.Sprintf("command timed out after %v", timeout)
+
+	case <-ctx.Done():
+		killProcessGroup(cmd)
+		<-done
 	}
 }
 
 // executeWorkflowTest executes a workflow-type test case
-func (e *UnifiedTestExecutor) executeWorkflowTest(tc *TestCase, result *TestResult) {
+func (e *UnifiedTestExecutor) executeWorkflowTest(ctx context.Context, tc *TestCase, result *TestResult) {
 	// Step 1: Determine Mode 1 (workflowId) or Mode 2 (workflowDef)
 	var workflowID string
 	var workflowDef interface{}
@@ -331,7 +517,7 @@ func (e *UnifiedTestExecutor) executeWorkflowTest(tc *TestCase, result *TestResu
 	}
 
 	// Step 3: Execute workflow
-	workflowResult, err := e.workflowExecutor.Execute(workflowID, workflowDef)
+	workflowResult, err := e.workflowExecutor.ExecuteContext(ctx, workflowID, workflowDef)
 	if err != nil {
 		result.Status = "error"
 		result.Error = fmt.Sprintf("workflow execution failed: %v", err)
@@ -362,135 +548,119 @@ func convertWorkflowStatusToTestStatus(workflowStatus string) string {
 	case "failed":
 		return "failed"
 	case "cancelled":
-		return "skipped"
+		return "cancelled"
+	case "timeout":
+		return "timeout"
 	default:
 		return "error"
 	}
 }
 
 // runHTTPAssertions runs HTTP assertions
-func (e *UnifiedTestExecutor) runHTTPAssertions(assertions []Assertion, statusCode int, body map[string]interface{}, result *TestResult) {
+func (e *UnifiedTestExecutor) runHTTPAssertions(assertions []Assertion, resp *http.Response, cookies []*http.Cookie, body *httpResponseBody, elapsed time.Duration, activation map[string]interface{}, result *TestResult) {
 	for _, assertion := range assertions {
+		var actual interface{}
+		var failure string
 		switch assertion.Type {
 		case "status_code":
-			if !e.checkStatusCode(assertion, statusCode) {
-				result.Status = "failed"
-				result.Failures = append(result.Failures,
-					fmt.Sprintf("status code: expected %v, got %d", assertion.Expected, statusCode))
-			}
+			actual = resp.StatusCode
+			failure = checkOperator(assertion, "status code", actual, true, "equals")
+
+		case "header":
+			actual, failure = checkHeader(assertion, resp.Header)
+
+		case "cookie":
+			actual, failure = checkCookie(assertion, cookies)
+
+		case "response_time":
+			actual, failure = checkResponseTime(assertion, elapsed)
 
 		case "json_path":
-			if !e.checkJSONPath(assertion, body, result) {
-				result.Status = "failed"
-			}
+			actual, failure = checkJSONPath(assertion, body.Data)
+
+		case "json_schema":
+			recordAssertion(result, assertion, nil, e.checkJSONSchema(assertion, body.Data)...)
+			continue
+
+		case "xpath":
+			actual, failure = checkXPath(assertion, body)
+
+		case "body":
+			actual = string(body.Raw)
+			failure = checkOperator(assertion, "body", actual, true, "contains")
+
+		case "body_size":
+			actual = len(body.Raw)
+			failure = checkOperator(assertion, "body size", actual, true, "equals")
+
+		case "body_sha256":
+			actual, failure = checkBodySHA256(assertion, body)
+
+		case "snapshot":
+			actual, failure = e.checkSnapshot(result.TestID, assertion, body.snapshotSubject())
+
+		case "expr":
+			actual, failure = checkExpr(assertion, activation)
+
+		default:
+			failure = fmt.Sprintf("unsupported assertion type for http test: %s", assertion.Type)
 		}
+
+		recordAssertion(result, assertion, actual, failure)
 	}
 }
 
 // runCommandAssertions runs command assertions
-func (e *UnifiedTestExecutor) runCommandAssertions(assertions []Assertion, exitCode int, stdout string, result *TestResult) {
+func (e *UnifiedTestExecutor) runCommandAssertions(assertions []Assertion, exitCode int, stdout, stderr string, activation map[string]interface{}, result *TestResult) {
 	for _, assertion := range assertions {
+		var actual interface{}
+		var failure string
 		switch assertion.Type {
 		case "exit_code":
-			expected, ok := assertion.Expected.(float64)
-			if !ok {
-				expectedInt, ok := assertion.Expected.(int)
-				if ok {
-					expected = float64(expectedInt)
-				}
-			}
-			if exitCode != int(expected) {
-				result.Status = "failed"
-				result.Failures = append(result.Failures,
-					fmt.Sprintf("exit code: expected %v, got %d", assertion.Expected, exitCode))
-			}
-
-		case "stdout_contains":
-			expected, ok := assertion.Expected.(string)
-			if !ok || !strings.Contains(stdout, expected) {
-				result.Status = "failed"
-				result.Failures = append(result.Failures,
-					fmt.Sprintf("stdout should contain: %v", assertion.Expected))
-			}
-		}
-	}
-}
+			actual = exitCode
+			failure = checkOperator(assertion, "exit code", actual, true, "equals")
 
-// checkStatusCode checks status code assertion
-func (e *UnifiedTestExecutor) checkStatusCode(assertion Assertion, actualCode int) bool {
-	if assertion.Operator == "in" {
-		// Expected is an array of valid status codes
-		if arr, ok := assertion.Expected.([]interface{}); ok {
-			for _, v := range arr {
-				if code, ok := v.(float64); ok && int(code) == actualCode {
-					return true
-				}
-			}
-			return false
-		}
-	}
+		case "stdout", "stdout_contains":
+			actual = stdout
+			failure = checkOperator(assertion, "stdout", actual, true, "contains")
 
-	// Exact match
-	if expected, ok := assertion.Expected.(float64); ok {
-		return int(expected) == actualCode
-	}
-	if expected, ok := assertion.Expected.(int); ok {
-		return expected == actualCode
-	}
+		case "stderr", "stderr_contains":
+			actual = stderr
+			failure = checkOperator(assertion, "stderr", actual, true, "contains")
 
-	return false
-}
+		case "snapshot":
+			actual, failure = e.checkSnapshot(result.TestID, assertion, stdout)
 
-// checkJSONPath checks JSON path assertion
-func (e *UnifiedTestExecutor) checkJSONPath(assertion Assertion, body map[string]interface{}, result *TestResult) bool {
-	// Simple JSON path implementation (only supports $.field notation)
-	path := strings.TrimPrefix(assertion.Path, "$.")
+		case "expr":
+			actual, failure = checkExpr(assertion, activation)
 
-	var value interface{}
-	if strings.Contains(path, ".") {
-		// Nested path
-		parts := strings.Split(path, ".")
-		current := body
-		for i, part := range parts {
-			if i == len(parts)-1 {
-				value = current[part]
-			} else {
-				if next, ok := current[part].(map[string]interface{}); ok {
-					current = next
-				} else {
-					result.Failures = append(result.Failures,
-						fmt.Sprintf("JSON path %s not found", assertion.Path))
-					return false
-				}
-			}
+		default:
+			failure = fmt.Sprintf("unsupported assertion type for command test: %s", assertion.Type)
 		}
-	} else {
-		value = body[path]
+
+		recordAssertion(result, assertion, actual, failure)
 	}
+}
 
-	if assertion.Operator == "exists" {
-		if value == nil {
-			result.Failures = append(result.Failures,
-				fmt.Sprintf("JSON path %s should exist", assertion.Path))
-			return false
-		}
-		return true
+// checkJSONPath checks JSON path assertion, returning the looked-up value and a failure message
+func checkJSONPath(assertion Assertion, body interface{}) (interface{}, string) {
+	value, found, err := LookupJSONPath(body, assertion.Path)
+	if err != nil {
+		return nil, err.Error()
 	}
 
-	// Exact match
-	if value != assertion.Expected {
-		result.Failures = append(result.Failures,
-			fmt.Sprintf("JSON path %s: expected %v, got %v", assertion.Path, assertion.Expected, value))
-		return false
+	// An empty match list is still a value for length checks
+	if _, isList := value.([]interface{}); isList && normalizeOperator(assertion.Operator, "equals") == "length" {
+		found = true
 	}
 
-	return true
+	return value, checkOperator(assertion, "JSON path "+assertion.Path, value, found, "equals")
 }
 
 // executeSetupHooks runs setup hooks before test execution
-func (e *UnifiedTestExecutor) executeSetupHooks(tc *TestCase, result *TestResult, ctx map[string]interface{}) bool {
+func (e *UnifiedTestExecutor) executeSetupHooks(ctx context.Context, tc *TestCase, result *TestResult, hookCtx map[string]interface{}) bool {
 	for _, hook := range tc.SetupHooks {
-		if !e.executeHook(&hook, "setup", result, ctx) {
+		if !e.executeHook(ctx, &hook, "setup", result, hookCtx) {
 			// Setup hook failed
 			if !hook.ContinueOnError {
 				result.Status = "error"
@@ -503,16 +673,16 @@ func (e *UnifiedTestExecutor) executeSetupHooks(tc *TestCase, result *TestResult
 }
 
 // executeTeardownHooks runs teardown hooks after test execution
-func (e *UnifiedTestExecutor) executeTeardownHooks(tc *TestCase, result *TestResult, ctx map[string]interface{}) {
+func (e *UnifiedTestExecutor) executeTeardownHooks(ctx context.Context, tc *TestCase, result *TestResult, hookCtx map[string]interface{}) {
 	for _, hook := range tc.TeardownHooks {
 		// Check if hook should run on failure
-		if result.Status == "failed" || result.Status == "error" {
+		if result.Status != "passed" {
 			if !hook.RunOnFailure {
 				continue
 			}
 		}
 
-		if !e.executeHook(&hook, "teardown", result, ctx) {
+		if !e.executeHook(ctx, &hook, "teardown", result, hookCtx) {
 			// Teardown hook failed, but don't fail the test
 			if result.Status == "passed" && !hook.ContinueOnError {
 				result.Status = "failed"
@@ -524,14 +694,18 @@ func (e *UnifiedTestExecutor) executeTeardownHooks(tc *TestCase, result *TestRes
 }
 
 // executeHook executes a single hook
-func (e *UnifiedTestExecutor) executeHook(hook *Hook, phase string, result *TestResult, ctx map[string]interface{}) bool {
+func (e *UnifiedTestExecutor) executeHook(ctx context.Context, hook *Hook, phase string, result *TestResult, hookCtx map[string]interface{}) bool {
 	fmt.Printf("[%s hook] Executing: %s (type: %s)\n", phase, hook.Name, hook.Type)
+	ctx = withHARComment(ctx, phase+" hook "+hook.Name)
+	hook = templateHook(hook, hookCtx)
 
 	switch hook.Type {
 	case "http":
-		return e.executeHTTPHook(hook, result, ctx)
+		return e.executeHTTPHook(ctx, hook, result, hookCtx)
 	case "command":
-		return e.executeCommandHook(hook, result, ctx)
+		return e.executeCommandHook(ctx, hook, result, hookCtx)
+	case "sql":
+		return e.executeSQLHook(ctx, hook, result, hookCtx)
 	default:
 		fmt.Printf("[%s hook] Unknown hook type: %s\n", phase, hook.Type)
 		return false
@@ -539,37 +713,47 @@ func (e *UnifiedTestExecutor) executeHook(hook *Hook, phase string, result *Test
 }
 
 // executeHTTPHook executes an HTTP hook
-func (e *UnifiedTestExecutor) executeHTTPHook(hook *Hook, result *TestResult, ctx map[string]interface{}) bool {
+func (e *UnifiedTestExecutor) executeHTTPHook(ctx context.Context, hook *Hook, result *TestResult, hookCtx map[string]interface{}) bool {
 	if hook.HTTP == nil {
 		return false
 	}
 
 	// Prepare request body
-	var bodyReader io.Reader
-	if hook.HTTP.Body != nil {
-		bodyBytes, err := json.Marshal(hook.HTTP.Body)
-		if err != nil {
-			fmt.Printf("[HTTP hook] Failed to marshal body: %v\n", err)
-			return false
-		}
-		bodyReader = bytes.NewReader(bodyBytes)
+	requestBody, err := encodeHTTPBody(hook.HTTP)
+	if err != nil {
+		fmt.Printf("[HTTP hook] Failed to prepare body: %v\n", err)
+		return false
+	}
+
+	// Resolve authentication credentials
+	auth, err := e.resolveAuth(hook.HTTP.Auth, hookCtx)
+	if err != nil {
+		fmt.Printf("[HTTP hook] %v\n", err)
+		return false
 	}
 
 	// Build URL
 	url := e.baseURL + hook.HTTP.Path
-	req, err := http.NewRequest(hook.HTTP.Method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, hook.HTTP.Method, url, requestBody.reader())
 	if err != nil {
 		fmt.Printf("[HTTP hook] Failed to create request: %v\n", err)
 		return false
 	}
 
 	// Set headers
-	for k, v := range hook.HTTP.Headers {
-		req.Header.Set(k, v)
+	setRequestHeaders(req, hook.HTTP.Headers, requestBody)
+	if err := e.applyAuth(req, auth, requestBody); err != nil {
+		fmt.Printf("[HTTP hook] %v\n", err)
+		return false
 	}
 
 	// Execute request
-	resp, err := e.client.Do(req)
+	client, err := e.httpClient()
+	if err != nil {
+		fmt.Printf("[HTTP hook] %v\n", err)
+		return false
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		fmt.Printf("[HTTP hook] Request failed: %v\n", err)
 		return false
@@ -578,18 +762,16 @@ func (e *UnifiedTestExecutor) executeHTTPHook(hook *Hook, result *TestResult, ct
 
 	// Read response
 	bodyBytes, _ := io.ReadAll(resp.Body)
-	var responseBody map[string]interface{}
+	var responseBody interface{}
 	json.Unmarshal(bodyBytes, &responseBody)
 
 	// Save response if requested
-	if hook.SaveResponse != "" {
-		ctx[hook.SaveResponse] = map[string]interface{}{
-			"statusCode": resp.StatusCode,
-			"body":       responseBody,
-			"bodyRaw":    string(bodyBytes),
-		}
-		fmt.Printf("[HTTP hook] Saved response to context: %s\n", hook.SaveResponse)
-	}
+	extracted := saveHookResponse(hook, "HTTP", map[string]interface{}{
+		"statusCode": resp.StatusCode,
+		"body":       responseBody,
+		"bodyRaw":    string(bodyBytes),
+		"cookies":    cookieValues(sessionCookies(ctx, resp)),
+	}, hookCtx)
 
 	// Consider 2xx status codes as success
 	success := resp.StatusCode >= 200 && resp.StatusCode < 300
@@ -597,23 +779,21 @@ func (e *UnifiedTestExecutor) executeHTTPHook(hook *Hook, result *TestResult, ct
 		fmt.Printf("[HTTP hook] Failed with status code: %d\n", resp.StatusCode)
 	}
 
-	return success
+	return success && extracted
 }
 
 // executeCommandHook executes a command hook
-func (e *UnifiedTestExecutor) executeCommandHook(hook *Hook, result *TestResult, ctx map[string]interface{}) bool {
+func (e *UnifiedTestExecutor) executeCommandHook(ctx context.Context, hook *Hook, result *TestResult, hookCtx map[string]interface{}) bool {
 	if hook.Command == nil {
 		return false
 	}
 
-	cmd := exec.Command(hook.Command.Cmd, hook.Command.Args...)
-	if hook.Command.Cwd != "" {
-		cmd.Dir = hook.Command.Cwd
+	prepared, err := e.prepareCommand(hook.Command, hookCtx)
+	if err != nil {
+		fmt.Printf("[Command hook] %v\n", err)
+		return false
 	}
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	cmd := prepared.cmd
 
 	// Set timeout
 	timeout := 60 * time.Second
@@ -621,15 +801,22 @@ func (e *UnifiedTestExecutor) executeCommandHook(hook *Hook, result *TestResult,
 		timeout = time.Duration(hook.Command.Timeout) * time.Second
 	}
 
+	if err := cmd.Start(); err != nil {
+		prepared.cleanup()
+		fmt.Printf("[Command hook] Failed: %v\n", err)
+		return false
+	}
 	done := make(chan error, 1)
 	go func() {
-		done <- cmd.Run()
+		err := cmd.Wait()
+		prepared.cleanup()
+		done <- err
 	}()
 
 	select {
 	case err := <-done:
 		exitCode := 0
-		if err != nil {
+		if err != nil && !errors.Is(err, exec.ErrWaitDelay) {
 			if exitErr, ok := err.(*exec.ExitError); ok {
 				exitCode = exitErr.ExitCode()
 			} else {
@@ -639,25 +826,66 @@ func (e *UnifiedTestExecutor) executeCommandHook(hook *Hook, result *TestResult,
 		}
 
 		// Save response if requested
-		if hook.SaveResponse != "" {
-			ctx[hook.SaveResponse] = map[string]interface{}{
-				"exitCode": exitCode,
-				"stdout":   stdout.String(),
-				"stderr":   stderr.String(),
-			}
-			fmt.Printf("[Command hook] Saved response to context: %s\n", hook.SaveResponse)
-		}
+		extracted := saveHookResponse(hook, "Command", map[string]interface{}{
+			"exitCode": exitCode,
+			"stdout":   prepared.stdout.String(),
+			"stderr":   prepared.stderr.String(),
+		}, hookCtx)
 
 		success := exitCode == 0
 		if !success {
 			fmt.Printf("[Command hook] Failed with exit code: %d\n", exitCode)
 		}
-		return success
+		return success && extracted
 
 	case <-time.After(timeout):
-		cmd.Process.Kill()
+		killProcessGroup(cmd)
+		<-done
 		fmt.Printf("[Command hook] Timeout after %v\n", timeout)
 		return false
+
+	case <-ctx.Done():
+		killProcessGroup(cmd)
+		<-done
+		fmt.Printf("[Command hook] Interrupted: %v\n", context.Cause(ctx))
+		return false
 	}
 }
 
+// executeSQLHook executes a SQL hook
+func (e *UnifiedTestExecutor) executeSQLHook(ctx context.Context, hook *Hook, result *TestResult, hookCtx map[string]interface{}) bool {
+	if hook.SQL == nil {
+		return false
+	}
+
+	qr, err := e.runQuery(ctx, hook.SQL)
+	if err != nil {
+		fmt.Printf("[SQL hook] Query failed: %v\n", err)
+		return false
+	}
+
+	// Save response if requested
+	return saveHookResponse(hook, "SQL", qr.toMap(), hookCtx)
+}
+
+// saveHookResponse stores a hook response in the context under SaveResponse
+// and stores each Extract JSONPath match under its variable name. It returns false if an extraction fails.
+func saveHookResponse(hook *Hook, label string, response map[string]interface{}, ctx map[string]interface{}) bool {
+	if hook.SaveResponse != "" {
+		ctx[hook.SaveResponse] = response
+		fmt.Printf("[%s hook] Saved response to context: %s\n", label, hook.SaveResponse)
+	}
+
+	ok := true
+	for name, path := range hook.Extract {
+		value, found, err := LookupJSONPath(response, path)
+		if err != nil || !found {
+			fmt.Printf("[%s hook] Failed to extract %s from %s: %v\n", label, name, path, err)
+			ok = false
+			continue
+		}
+		ctx[name] = value
+		fmt.Printf("[%s hook] Extracted %s to context\n", label, name)
+	}
+	return ok
+}
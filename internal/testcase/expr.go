@@ -0,0 +1,151 @@
+package testcase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+)
+
+const (
+	// exprCostLimit bounds the work a single expression may do
+	exprCostLimit = 1000000
+	// exprTimeout bounds the wall-clock time of a single evaluation
+	exprTimeout = time.Second
+)
+
+// exprVariables are the names visible to expr assertions
+var exprVariables = []string{"status", "headers", "cookies", "body", "duration", "stdout", "stderr", "exitCode", "vars"}
+
+var (
+	exprEnvOnce sync.Once
+	exprEnv     *cel.Env
+	exprEnvErr  error
+
+	// exprProgramCache caches compiled programs by expression source
+	exprProgramCache sync.Map
+)
+
+// exprProgramEntry is a cached compilation result
+type exprProgramEntry struct {
+	program cel.Program
+	err     error
+}
+
+// compileExpr compiles an expression once and caches the program for later runs.
+// Expressions run in a CEL environment with no I/O and a cost limit.
+func compileExpr(source string) (cel.Program, error) {
+	if cached, ok := exprProgramCache.Load(source); ok {
+		entry := cached.(*exprProgramEntry)
+		return entry.program, entry.err
+	}
+
+	exprEnvOnce.Do(func() {
+		options := make([]cel.EnvOption, 0, len(exprVariables))
+		for _, name := range exprVariables {
+			options = append(options, cel.Variable(name, cel.DynType))
+		}
+		exprEnv, exprEnvErr = cel.NewEnv(options...)
+	})
+	if exprEnvErr != nil {
+		return nil, exprEnvErr
+	}
+
+	entry := &exprProgramEntry{}
+	ast, issues := exprEnv.Compile(source)
+	if issues != nil && issues.Err() != nil {
+		entry.err = issues.Err()
+	} else {
+		entry.program, entry.err = exprEnv.Program(ast,
+			cel.CostLimit(exprCostLimit),
+			cel.InterruptCheckFrequency(100),
+		)
+	}
+
+	exprProgramCache.Store(source, entry)
+	return entry.program, entry.err
+}
+
+// checkExpr evaluates an expr assertion, whose expected value is the expression, against the activation
+func checkExpr(assertion Assertion, activation map[string]interface{}) (interface{}, string) {
+	source, ok := assertion.Expected.(string)
+	if !ok || strings.TrimSpace(source) == "" {
+		return nil, "expr: expected must be an expression string"
+	}
+
+	program, err := compileExpr(source)
+	if err != nil {
+		return nil, fmt.Sprintf("expr %q: %v", source, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), exprTimeout)
+	defer cancel()
+
+	out, _, err := program.ContextEval(ctx, activation)
+	if err != nil {
+		return nil, fmt.Sprintf("expr %q: %v", source, err)
+	}
+
+	value, ok := out.Value().(bool)
+	if !ok {
+		return out.Value(), fmt.Sprintf("expr %q: must evaluate to a bool, got %s", source, describeValue(out.Value()))
+	}
+	if !value {
+		return false, fmt.Sprintf("expr %q evaluated to false", source)
+	}
+	return true, ""
+}
+
+// exprActivation builds the variables visible to expr assertions from the given values,
+// or returns nil when none of the assertions is an expr
+func (e *UnifiedTestExecutor) exprActivation(assertions []Assertion, hookCtx map[string]interface{}, values map[string]interface{}) map[string]interface{} {
+	hasExpr := false
+	for _, assertion := range assertions {
+		if assertion.Type == "expr" {
+			hasExpr = true
+			break
+		}
+	}
+	if !hasExpr {
+		return nil
+	}
+
+	activation := make(map[string]interface{}, len(exprVariables))
+	for _, name := range exprVariables {
+		activation[name] = nil
+	}
+	for k, v := range values {
+		activation[k] = v
+	}
+	activation["vars"] = e.resolvedVariables(hookCtx)
+	return activation
+}
+
+// resolvedVariables merges the active environment's variables with values saved by setup hooks
+func (e *UnifiedTestExecutor) resolvedVariables(hookCtx map[string]interface{}) map[string]interface{} {
+	vars := make(map[string]interface{})
+	if e.variableInjector != nil {
+		if envVars, err := e.variableInjector.GetActiveEnvironmentVariables(); err == nil {
+			for k, v := range envVars {
+				vars[k] = v
+			}
+		}
+	}
+	for k, v := range hookCtx {
+		vars[k] = normalizeJSON(v)
+	}
+	return vars
+}
+
+// exprHeaders flattens response headers for expressions, keyed by lower-case name
+func exprHeaders(header http.Header) map[string]interface{} {
+	headers := make(map[string]interface{}, len(header))
+	for name, values := range header {
+		headers[strings.ToLower(name)] = strings.Join(values, ", ")
+	}
+	return headers
+}
@@ -0,0 +1,79 @@
+package testcase
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExpr_HTTPContext tests expressions over status, headers, body, duration and hook variables
+func TestExpr_HTTPContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/login":
+			w.Write([]byte(`{"user": "alice"}`))
+		default:
+			w.Write([]byte(`{"owner": "alice", "items": [{"price": 10}, {"price": 2.5}]}`))
+		}
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	result := executor.Execute(&TestCase{
+		ID:   "expr-http",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "GET", Path: "/cart"},
+		SetupHooks: []Hook{
+			{Type: "http", Name: "login", HTTP: &HTTPTest{Method: "POST", Path: "/login"}, Extract: map[string]string{"user": "$.body.user"}},
+		},
+		Assertions: []Assertion{
+			{Type: "expr", Expected: "body.items.all(i, i.price > 0) && status == 200"},
+			{Type: "expr", Expected: `headers["content-type"].startsWith("application/json")`},
+			{Type: "expr", Expected: "body.owner == vars.user && duration < 5000.0"},
+			{Type: "expr", Expected: "size(body.items) == 3"},
+		},
+	})
+
+	assert.Equal(t, "failed", result.Status)
+	require.Len(t, result.Failures, 1, "failures: %v", result.Failures)
+	assert.Equal(t, `expr "size(body.items) == 3" evaluated to false`, result.Failures[0])
+	assert.Equal(t, true, result.Assertions[0].Actual)
+}
+
+// TestExpr_CommandContext tests expressions over command output
+func TestExpr_CommandContext(t *testing.T) {
+	executor := NewExecutor("")
+	result := executor.Execute(&TestCase{
+		ID:      "expr-command",
+		Type:    "command",
+		Command: &CommandTest{Cmd: "sh", Args: []string{"-c", `echo '{"count": 3}'; echo warn >&2`}},
+		Assertions: []Assertion{
+			{Type: "expr", Expected: `exitCode == 0 && body.count == 3 && stderr.contains("warn")`},
+			{Type: "expr", Expected: `stdout.matches("count")`},
+		},
+	})
+
+	assert.Equal(t, "passed", result.Status, "failures: %v", result.Failures)
+}
+
+// TestExpr_Errors tests compile errors, non-bool results and the cost limit
+func TestExpr_Errors(t *testing.T) {
+	activation := map[string]interface{}{"body": map[string]interface{}{"n": 1.0}}
+
+	_, failure := checkExpr(Assertion{Type: "expr", Expected: "body.n =="}, activation)
+	assert.Contains(t, failure, "Syntax error")
+
+	actual, failure := checkExpr(Assertion{Type: "expr", Expected: "body.n + 1.0"}, activation)
+	assert.Equal(t, 2.0, actual)
+	assert.Contains(t, failure, "must evaluate to a bool")
+
+	_, failure = checkExpr(Assertion{Type: "expr", Expected: "[1,2,3,4,5,6,7,8,9,10].all(a, [1,2,3,4,5,6,7,8,9,10].all(b, [1,2,3,4,5,6,7,8,9,10].all(c, [1,2,3,4,5,6,7,8,9,10].all(d, [1,2,3,4,5,6,7,8,9,10].all(e, [1,2,3,4,5,6,7,8,9,10].all(f, a+b+c+d+e+f > 0))))))"}, activation)
+	assert.Contains(t, failure, "cost limit")
+
+	_, failure = checkExpr(Assertion{Type: "expr"}, activation)
+	assert.Equal(t, "expr: expected must be an expression string", failure)
+}
@@ -0,0 +1,49 @@
+package testcase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ExecuteGroupSetupHooks runs the setup hooks of a test group once before its tests.
+// Hooks can reference the variables already in vars, and the values they save
+// (saveResponse and extract) are added to vars, so every test of the run can use them.
+// It stops at the first failed hook without continueOnError and returns an error naming it.
+func (e *UnifiedTestExecutor) ExecuteGroupSetupHooks(ctx context.Context, groupID string, hooks []Hook, vars *RunVariables) error {
+	hookCtx := vars.Values()
+	defer saveGroupVariables(hookCtx, vars)
+
+	result := &TestResult{TestID: groupID}
+	for _, hook := range hooks {
+		if !e.executeHook(ctx, &hook, "group setup", result, hookCtx) && !hook.ContinueOnError {
+			return fmt.Errorf("group %s: setup hook '%s' failed", groupID, hook.Name)
+		}
+	}
+	return nil
+}
+
+// ExecuteGroupTeardownHooks runs the teardown hooks of a test group once after its tests.
+// Every hook runs, regardless of runOnFailure, even when setup or tests failed or ctx is done.
+// It returns an error naming the failed hooks without continueOnError.
+func (e *UnifiedTestExecutor) ExecuteGroupTeardownHooks(ctx context.Context, groupID string, hooks []Hook, vars *RunVariables) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), teardownTimeout)
+	defer cancel()
+
+	hookCtx := vars.Values()
+	result := &TestResult{TestID: groupID}
+	var errs []error
+	for _, hook := range hooks {
+		if !e.executeHook(ctx, &hook, "group teardown", result, hookCtx) && !hook.ContinueOnError {
+			errs = append(errs, fmt.Errorf("group %s: teardown hook '%s' failed", groupID, hook.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// saveGroupVariables adds the values saved by group hooks to the run variables
+func saveGroupVariables(hookCtx map[string]interface{}, vars *RunVariables) {
+	for name, value := range hookCtx {
+		vars.Set(name, value)
+	}
+}
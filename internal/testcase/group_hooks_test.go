@@ -0,0 +1,96 @@
+package testcase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGroupHooks_SharedAcrossTests tests that group setup hooks run once and their saved values reach every test
+func TestGroupHooks_SharedAcrossTests(t *testing.T) {
+	var logins, logouts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			atomic.AddInt32(&logins, 1)
+			w.Write([]byte(`{"token": "t-group", "tenant": {"id": 7}}`))
+		case "/logout":
+			if r.Header.Get("Authorization") == "Bearer t-group" {
+				atomic.AddInt32(&logouts, 1)
+			}
+		default:
+			if r.Header.Get("Authorization") != "Bearer t-group" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	vars := NewRunVariables()
+	ctx := WithRunVariables(context.Background(), vars)
+
+	setup := []Hook{
+		{Type: "http", Name: "login", HTTP: &HTTPTest{Method: "POST", Path: "/login"}, SaveResponse: "login", Extract: map[string]string{"token": "$.body.token"}},
+	}
+	teardown := []Hook{
+		{Type: "http", Name: "logout", HTTP: &HTTPTest{Method: "POST", Path: "/logout", Headers: map[string]string{"Authorization": "Bearer {{token}}"}}},
+	}
+	assert.NoError(t, executor.ExecuteGroupSetupHooks(ctx, "suite", setup, vars))
+
+	for _, id := range []string{"orders", "invoices"} {
+		result := executor.ExecuteContext(ctx, &TestCase{
+			ID:   id,
+			Type: "http",
+			HTTP: &HTTPTest{Method: "GET", Path: "/" + id, Headers: map[string]string{"Authorization": "Bearer {{token}}"}},
+			Assertions: []Assertion{
+				{Type: "status_code", Expected: 200},
+				{Type: "expr", Expected: `vars.login.body.tenant.id == 7.0`},
+			},
+		})
+		assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+	}
+
+	assert.NoError(t, executor.ExecuteGroupTeardownHooks(ctx, "suite", teardown, vars))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&logins))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&logouts))
+}
+
+// TestGroupHooks_Failures tests that failed group hooks are reported and teardown runs after the run is interrupted
+func TestGroupHooks_Failures(t *testing.T) {
+	var cleanups int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cleanup":
+			atomic.AddInt32(&cleanups, 1)
+		case "/seed":
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	vars := NewRunVariables()
+
+	err := executor.ExecuteGroupSetupHooks(context.Background(), "suite", []Hook{
+		{Type: "http", Name: "optional", HTTP: &HTTPTest{Method: "POST", Path: "/seed"}, ContinueOnError: true},
+		{Type: "http", Name: "seed", HTTP: &HTTPTest{Method: "POST", Path: "/seed"}},
+		{Type: "http", Name: "never", HTTP: &HTTPTest{Method: "POST", Path: "/cleanup"}},
+	}, vars)
+	assert.EqualError(t, err, "group suite: setup hook 'seed' failed")
+	assert.Equal(t, int32(0), atomic.LoadInt32(&cleanups))
+
+	// Teardown hooks all run, even under a cancelled context, and failures are collected
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = executor.ExecuteGroupTeardownHooks(ctx, "suite", []Hook{
+		{Type: "http", Name: "unseed", HTTP: &HTTPTest{Method: "DELETE", Path: "/seed"}},
+		{Type: "http", Name: "cleanup", HTTP: &HTTPTest{Method: "POST", Path: "/cleanup"}},
+	}, vars)
+	assert.EqualError(t, err, "group suite: teardown hook 'unseed' failed")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&cleanups))
+}
@@ -0,0 +1,480 @@
+package testcase
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// executeGRPC executes a gRPC test (unary or server-streaming)
+func (e *UnifiedTestExecutor) executeGRPC(ctx context.Context, tc *TestCase, result *TestResult) {
+	if tc.GRPC == nil {
+		result.Status = "error"
+		result.Error = "gRPC configuration missing"
+		return
+	}
+
+	timeout := 30 * time.Second
+	if tc.GRPC.Timeout > 0 {
+		timeout = time.Duration(tc.GRPC.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	address := tc.GRPC.Address
+	if address == "" {
+		address = grpcAddressFromBaseURL(e.baseURL)
+	}
+
+	// TLS connections use the group's and environment's CA, client certificate and verification settings
+	var tlsConfig *tls.Config
+	if tc.GRPC.TLS {
+		var err error
+		tlsConfig, err = e.clientTLSConfig()
+		if err != nil {
+			result.Status = "error"
+			result.Error = err.Error()
+			return
+		}
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+	}
+
+	conn, err := dialGRPC(address, tlsConfig)
+	if err != nil {
+		result.Status = "error"
+		result.Error = fmt.Sprintf("failed to connect: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	// Resolve the method schema from the descriptor set or server reflection
+	method, err := resolveGRPCMethod(ctx, conn, tc.GRPC)
+	if err != nil {
+		result.Status = "error"
+		result.Error = fmt.Sprintf("failed to resolve method: %v", err)
+		return
+	}
+	if method.IsStreamingClient() {
+		result.Status = "error"
+		result.Error = fmt.Sprintf("client streaming method %s is not supported", method.FullName())
+		return
+	}
+
+	// Build request message
+	req := dynamicpb.NewMessage(method.Input())
+	if tc.GRPC.Message != nil {
+		data, err := json.Marshal(tc.GRPC.Message)
+		if err != nil {
+			result.Status = "error"
+			result.Error = fmt.Sprintf("failed to marshal message: %v", err)
+			return
+		}
+		if err := protojson.Unmarshal(data, req); err != nil {
+			result.Status = "error"
+			result.Error = fmt.Sprintf("invalid request message for %s: %v", method.Input().FullName(), err)
+			return
+		}
+	}
+
+	fullMethod := fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name())
+	if len(tc.GRPC.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(tc.GRPC.Metadata))
+	}
+
+	// Store request info
+	result.Request = map[string]interface{}{
+		"address":  address,
+		"method":   fullMethod,
+		"metadata": tc.GRPC.Metadata,
+		"message":  tc.GRPC.Message,
+	}
+
+	// Execute call
+	var header, trailer metadata.MD
+	var messages []interface{}
+	var callErr error
+	if method.IsStreamingServer() {
+		messages, header, trailer, callErr = invokeServerStream(ctx, conn, fullMethod, method, req, tc.GRPC.MaxMessages)
+	} else {
+		resp := dynamicpb.NewMessage(method.Output())
+		callErr = conn.Invoke(ctx, fullMethod, req, resp, grpc.Header(&header), grpc.Trailer(&trailer))
+		if callErr == nil {
+			messages = append(messages, grpcMessageToMap(resp))
+		}
+	}
+
+	st := status.Convert(callErr)
+
+	// Unary calls expose the response message as body; streams expose all received messages
+	var body map[string]interface{}
+	if method.IsStreamingServer() {
+		body = map[string]interface{}{"messages": messages}
+	} else if len(messages) > 0 {
+		body, _ = messages[0].(map[string]interface{})
+	}
+
+	result.Response = map[string]interface{}{
+		"statusCode":    int(st.Code()),
+		"status":        grpcCodeName(st.Code()),
+		"statusMessage": st.Message(),
+		"headers":       header,
+		"trailers":      trailer,
+		"body":          body,
+	}
+
+	// Run assertions
+	e.runGRPCAssertions(tc.Assertions, st, body, trailer, result)
+}
+
+// invokeServerStream sends a single request and collects every streamed response
+func invokeServerStream(ctx context.Context, conn *grpc.ClientConn, fullMethod string, method protoreflect.MethodDescriptor, req proto.Message, maxMessages int) ([]interface{}, metadata.MD, metadata.MD, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, fullMethod)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := stream.SendMsg(req); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	var messages []interface{}
+	for maxMessages <= 0 || len(messages) < maxMessages {
+		resp := dynamicpb.NewMessage(method.Output())
+		err := stream.RecvMsg(resp)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			header, _ := stream.Header()
+			return messages, header, stream.Trailer(), err
+		}
+		messages = append(messages, grpcMessageToMap(resp))
+	}
+
+	header, _ := stream.Header()
+	return messages, header, stream.Trailer(), nil
+}
+
+// runGRPCAssertions runs gRPC assertions
+func (e *UnifiedTestExecutor) runGRPCAssertions(assertions []Assertion, st *status.Status, body map[string]interface{}, trailer metadata.MD, result *TestResult) {
+	hasStatusAssertion := false
+
+	for _, assertion := range assertions {
+		var actual interface{}
+		var failure string
+		switch assertion.Type {
+		case "grpc_status":
+			hasStatusAssertion = true
+			actual = grpcCodeName(st.Code())
+			ok, err := checkGRPCStatus(assertion, st.Code())
+			if err != nil {
+				failure = fmt.Sprintf("grpc status: %v", err)
+			} else if !ok {
+				failure = fmt.Sprintf("grpc status: expected %v, got %s (%s)", assertion.Expected, grpcCodeName(st.Code()), st.Message())
+			}
+
+		case "json_path":
+			actual, failure = checkJSONPath(assertion, body)
+
+		case "trailer":
+			value, found := grpcTrailerValue(trailer, assertion.Path)
+			if found {
+				actual = value
+			}
+			failure = checkOperator(assertion, "trailer "+assertion.Path, value, found, "equals")
+
+		default:
+			failure = fmt.Sprintf("unsupported assertion type for grpc test: %s", assertion.Type)
+		}
+
+		recordAssertion(result, assertion, actual, failure)
+	}
+
+	// Without an explicit status assertion, anything other than OK is a failure
+	if !hasStatusAssertion && st.Code() != codes.OK {
+		recordAssertion(result, Assertion{Type: "grpc_status", Expected: "OK"}, grpcCodeName(st.Code()),
+			fmt.Sprintf("grpc status: expected OK, got %s (%s)", grpcCodeName(st.Code()), st.Message()))
+	}
+}
+
+// checkGRPCStatus checks a status assertion given as a code name ("NOT_FOUND") or number
+func checkGRPCStatus(assertion Assertion, actual codes.Code) (bool, error) {
+	matches := func(expected interface{}) bool {
+		switch v := expected.(type) {
+		case float64:
+			return codes.Code(v) == actual
+		case int:
+			return codes.Code(v) == actual
+		case string:
+			return normalizeGRPCCodeName(v) == normalizeGRPCCodeName(grpcCodeName(actual))
+		}
+		return false
+	}
+
+	switch op := normalizeOperator(assertion.Operator, "equals"); op {
+	case "equals":
+		return matches(assertion.Expected), nil
+	case "not_equals":
+		return !matches(assertion.Expected), nil
+	case "one_of":
+		arr, ok := assertion.Expected.([]interface{})
+		if !ok {
+			return false, fmt.Errorf("one_of expects an array, got %v", assertion.Expected)
+		}
+		for _, v := range arr {
+			if matches(v) {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("unsupported operator for grpc_status: %s", op)
+	}
+}
+
+// grpcTrailerValue returns a trailer's value: a string for a single value, a list for repeated keys
+func grpcTrailerValue(trailer metadata.MD, key string) (interface{}, bool) {
+	values := trailer.Get(key)
+	switch len(values) {
+	case 0:
+		return nil, false
+	case 1:
+		return values[0], true
+	default:
+		list := make([]interface{}, len(values))
+		for i, v := range values {
+			list[i] = v
+		}
+		return list, true
+	}
+}
+
+// grpcCodeName returns the canonical upper snake case name of a status code
+func grpcCodeName(code codes.Code) string {
+	switch code {
+	case codes.OK:
+		return "OK"
+	case codes.Canceled:
+		return "CANCELLED"
+	case codes.Unknown:
+		return "UNKNOWN"
+	case codes.InvalidArgument:
+		return "INVALID_ARGUMENT"
+	case codes.DeadlineExceeded:
+		return "DEADLINE_EXCEEDED"
+	case codes.NotFound:
+		return "NOT_FOUND"
+	case codes.AlreadyExists:
+		return "ALREADY_EXISTS"
+	case codes.PermissionDenied:
+		return "PERMISSION_DENIED"
+	case codes.ResourceExhausted:
+		return "RESOURCE_EXHAUSTED"
+	case codes.FailedPrecondition:
+		return "FAILED_PRECONDITION"
+	case codes.Aborted:
+		return "ABORTED"
+	case codes.OutOfRange:
+		return "OUT_OF_RANGE"
+	case codes.Unimplemented:
+		return "UNIMPLEMENTED"
+	case codes.Internal:
+		return "INTERNAL"
+	case codes.Unavailable:
+		return "UNAVAILABLE"
+	case codes.DataLoss:
+		return "DATA_LOSS"
+	case codes.Unauthenticated:
+		return "UNAUTHENTICATED"
+	default:
+		return code.String()
+	}
+}
+
+// normalizeGRPCCodeName lets "NOT_FOUND", "NotFound" and "not_found" compare equal
+func normalizeGRPCCodeName(name string) string {
+	name = strings.ToLower(strings.ReplaceAll(name, "_", ""))
+	if name == "canceled" {
+		return "cancelled"
+	}
+	return name
+}
+
+// grpcMessageToMap converts a protobuf message to its JSON map representation
+func grpcMessageToMap(msg proto.Message) map[string]interface{} {
+	data, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		return nil
+	}
+	var m map[string]interface{}
+	json.Unmarshal(data, &m)
+	return m
+}
+
+// grpcAddressFromBaseURL derives a host:port target from the executor base URL
+func grpcAddressFromBaseURL(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return baseURL
+	}
+	return u.Host
+}
+
+// dialGRPC creates a client connection to the target address, using TLS if tlsConfig is set
+func dialGRPC(address string, tlsConfig *tls.Config) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if tlsConfig != nil {
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	return grpc.NewClient(address, grpc.WithTransportCredentials(creds))
+}
+
+// resolveGRPCMethod finds the method descriptor for the configured service/method
+func resolveGRPCMethod(ctx context.Context, conn *grpc.ClientConn, config *GRPCTest) (protoreflect.MethodDescriptor, error) {
+	var files *protoregistry.Files
+	var err error
+	if config.DescriptorSet != "" {
+		files, err = filesFromDescriptorSet(config.DescriptorSet)
+	} else {
+		files, err = filesFromReflection(ctx, conn, config.Service)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(config.Service))
+	if err != nil {
+		return nil, fmt.Errorf("service %s not found: %w", config.Service, err)
+	}
+	service, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", config.Service)
+	}
+
+	method := service.Methods().ByName(protoreflect.Name(config.Method))
+	if method == nil {
+		return nil, fmt.Errorf("method %s not found in service %s", config.Method, config.Service)
+	}
+	return method, nil
+}
+
+// filesFromDescriptorSet decodes a base64 FileDescriptorSet (protoc --include_imports --descriptor_set_out)
+func filesFromDescriptorSet(encoded string) (*protoregistry.Files, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptor set encoding: %w", err)
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid descriptor set: %w", err)
+	}
+	return protodesc.NewFiles(&set)
+}
+
+// filesFromReflection downloads the file descriptors defining a service via server reflection
+func filesFromReflection(ctx context.Context, conn *grpc.ClientConn, service string) (*protoregistry.Files, error) {
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("server reflection unavailable: %w", err)
+	}
+	defer stream.CloseSend()
+
+	fileProtos := make(map[string]*descriptorpb.FileDescriptorProto)
+	request := func(req *reflectionpb.ServerReflectionRequest) error {
+		if err := stream.Send(req); err != nil {
+			return fmt.Errorf("server reflection unavailable: %w", err)
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			return fmt.Errorf("server reflection unavailable: %w", err)
+		}
+		if errResp := resp.GetErrorResponse(); errResp != nil {
+			return fmt.Errorf("server reflection error: %s", errResp.GetErrorMessage())
+		}
+		for _, raw := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			fd := &descriptorpb.FileDescriptorProto{}
+			if err := proto.Unmarshal(raw, fd); err != nil {
+				return fmt.Errorf("invalid file descriptor from server: %w", err)
+			}
+			fileProtos[fd.GetName()] = fd
+		}
+		return nil
+	}
+
+	err = request(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Servers usually send transitive dependencies along; fetch whatever is still missing
+	for missing := missingDependencies(fileProtos); len(missing) > 0; missing = missingDependencies(fileProtos) {
+		for _, name := range missing {
+			if _, ok := fileProtos[name]; ok {
+				continue
+			}
+			if fd, err := protoregistry.GlobalFiles.FindFileByPath(name); err == nil {
+				fileProtos[name] = protodesc.ToFileDescriptorProto(fd)
+				continue
+			}
+			err := request(&reflectionpb.ServerReflectionRequest{
+				MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: name},
+			})
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := fileProtos[name]; !ok {
+				return nil, fmt.Errorf("dependency %s not provided by server", name)
+			}
+		}
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, fd := range fileProtos {
+		set.File = append(set.File, fd)
+	}
+	return protodesc.NewFiles(set)
+}
+
+// missingDependencies lists imported files that have not been loaded yet
+func missingDependencies(fileProtos map[string]*descriptorpb.FileDescriptorProto) []string {
+	var missing []string
+	for _, fd := range fileProtos {
+		for _, dep := range fd.GetDependency() {
+			if _, ok := fileProtos[dep]; !ok {
+				missing = append(missing, dep)
+			}
+		}
+	}
+	return missing
+}
@@ -0,0 +1,208 @@
+package testcase
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// startGRPCServer starts a health service with reflection enabled and returns its address
+func startGRPCServer(t *testing.T, withReflection bool) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	// Echo the incoming x-request-id metadata back as a trailer
+	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("x-request-id")) > 0 {
+			grpc.SetTrailer(ctx, metadata.Pairs("x-request-id", md.Get("x-request-id")[0]))
+		}
+		return handler(ctx, req)
+	}
+
+	server := grpc.NewServer(grpc.UnaryInterceptor(interceptor))
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("orders", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	if withReflection {
+		reflection.Register(server)
+	}
+
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String()
+}
+
+// TestGRPC_UnaryWithReflection tests a unary call resolved via server reflection
+func TestGRPC_UnaryWithReflection(t *testing.T) {
+	addr := startGRPCServer(t, true)
+	executor := NewExecutor("http://" + addr)
+
+	result := executor.Execute(&TestCase{
+		ID:   "grpc-unary",
+		Name: "health check",
+		Type: "grpc",
+		GRPC: &GRPCTest{
+			Service:  "grpc.health.v1.Health",
+			Method:   "Check",
+			Message:  map[string]interface{}{"service": "orders"},
+			Metadata: map[string]string{"x-request-id": "req-42"},
+		},
+		Assertions: []Assertion{
+			{Type: "grpc_status", Expected: "OK"},
+			{Type: "json_path", Path: "$.status", Expected: "SERVING"},
+			{Type: "trailer", Path: "x-request-id", Expected: "req-42"},
+		},
+	})
+
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+	assert.Equal(t, "OK", result.Response["status"])
+}
+
+// TestGRPC_StatusAssertion tests asserting on a non-OK status code
+func TestGRPC_StatusAssertion(t *testing.T) {
+	addr := startGRPCServer(t, true)
+	executor := NewExecutor("http://127.0.0.1:1")
+
+	tc := &TestCase{
+		ID:   "grpc-not-found",
+		Type: "grpc",
+		GRPC: &GRPCTest{
+			Address: addr,
+			Service: "grpc.health.v1.Health",
+			Method:  "Check",
+			Message: map[string]interface{}{"service": "unknown"},
+		},
+		Assertions: []Assertion{
+			{Type: "grpc_status", Expected: "NOT_FOUND"},
+		},
+	}
+	result := executor.Execute(tc)
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+
+	// Without a status assertion a non-OK code fails the test
+	tc.Assertions = nil
+	result = executor.Execute(tc)
+	assert.Equal(t, "failed", result.Status)
+	assert.Len(t, result.Failures, 1)
+}
+
+// TestGRPC_ServerStreamingWithDescriptorSet tests a server-streaming call using an uploaded descriptor set
+func TestGRPC_ServerStreamingWithDescriptorSet(t *testing.T) {
+	addr := startGRPCServer(t, false)
+
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(healthpb.File_grpc_health_v1_health_proto),
+		},
+	}
+	data, err := proto.Marshal(set)
+	require.NoError(t, err)
+
+	executor := NewExecutor("http://" + addr)
+	result := executor.Execute(&TestCase{
+		ID:   "grpc-stream",
+		Type: "grpc",
+		GRPC: &GRPCTest{
+			Service:       "grpc.health.v1.Health",
+			Method:        "Watch",
+			Message:       map[string]interface{}{"service": "orders"},
+			DescriptorSet: base64.StdEncoding.EncodeToString(data),
+			MaxMessages:   1,
+		},
+	})
+
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+	body := result.Response["body"].(map[string]interface{})
+	messages := body["messages"].([]interface{})
+	require.Len(t, messages, 1)
+	assert.Equal(t, "SERVING", messages[0].(map[string]interface{})["status"])
+}
+
+// TestGRPC_ReflectionUnavailable tests the error reported when reflection is disabled
+func TestGRPC_ReflectionUnavailable(t *testing.T) {
+	addr := startGRPCServer(t, false)
+	executor := NewExecutor("http://" + addr)
+
+	result := executor.Execute(&TestCase{
+		ID:   "grpc-no-reflection",
+		Type: "grpc",
+		GRPC: &GRPCTest{Service: "grpc.health.v1.Health", Method: "Check"},
+	})
+
+	assert.Equal(t, "error", result.Status)
+	assert.Contains(t, result.Error, "failed to resolve method")
+}
+
+// TestGRPC_MutualTLS tests that TLS calls use the resolved CA, client certificate and SNI override
+func TestGRPC_MutualTLS(t *testing.T) {
+	ca := issueCertificate(t, "Test CA", nil, nil)
+	serverCert := issueCertificate(t, "grpc.internal", []string{"grpc.internal"}, ca)
+	clientCert := issueCertificate(t, "test-runner", nil, ca)
+
+	serverPair, err := tls.X509KeyPair([]byte(serverCert.certPEM), []byte(serverCert.keyPEM))
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverPair},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	})))
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("orders", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	injector := &fakeEnvironmentInjector{
+		envID: "dev",
+		vars: map[string]map[string]string{
+			"dev": {"CLIENT_CERT": clientCert.certPEM, "CLIENT_KEY": clientCert.keyPEM},
+		},
+	}
+	executor := NewExecutorWithInjector("https://"+lis.Addr().String(), nil, nil, nil, injector)
+	test := &TestCase{
+		ID:   "grpc-mtls",
+		Type: "grpc",
+		GRPC: &GRPCTest{
+			Service: "grpc.health.v1.Health",
+			Method:  "Check",
+			Message: map[string]interface{}{"service": "orders"},
+			TLS:     true,
+			Timeout: 5,
+		},
+		Assertions: []Assertion{{Type: "json_path", Path: "$.status", Expected: "SERVING"}},
+	}
+
+	// The system roots do not trust the test CA
+	result := executor.Execute(test)
+	assert.Equal(t, "error", result.Status)
+
+	group := &TLSConfig{CACert: ca.certPEM, ClientCert: "{{CLIENT_CERT}}", ClientKey: "{{CLIENT_KEY}}", ServerName: "grpc.internal"}
+	result = executor.WithTLSConfig(group).Execute(test)
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+
+	// Without a client certificate the server rejects the handshake
+	result = executor.WithTLSConfig(&TLSConfig{CACert: ca.certPEM, ServerName: "grpc.internal"}).Execute(test)
+	assert.Equal(t, "error", result.Status)
+}
@@ -0,0 +1,426 @@
+package testcase
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// harRedacted replaces the values of secret headers and parameters
+	harRedacted = "[REDACTED]"
+	// harMaxBodySize limits the request and response body text kept per entry
+	harMaxBodySize = 1 << 20
+)
+
+// harSensitiveNames are substrings of header, query and form parameter names, and of JSON body
+// keys, whose values are redacted
+var harSensitiveNames = []string{
+	"authorization", "cookie", "token", "secret", "password", "passwd",
+	"api-key", "apikey", "api_key", "signature", "credential", "session",
+}
+
+// HAR is an HTTP Archive (HAR 1.2) of the HTTP exchanges made during a test
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+// HARLog is the root of a HAR document
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Entries []HAREntry `json:"entries"`
+}
+
+// HARCreator identifies the application that recorded the archive
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HAREntry is a single recorded request and its response
+type HAREntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"` // milliseconds
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"` // where the request was made, e.g. "setup hook login" or "test"
+	Error           string      `json:"_error,omitempty"`  // transport error when no response was received
+}
+
+// HARRequest is a recorded request
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// HARResponse is a recorded response
+type HARResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	Content     HARContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// HARNameValue is a header, cookie, query or form parameter
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARPostData is a recorded request body
+type HARPostData struct {
+	MimeType string         `json:"mimeType"`
+	Text     string         `json:"text"`
+	Params   []HARNameValue `json:"params,omitempty"`
+	Comment  string         `json:"comment,omitempty"`
+}
+
+// HARContent is a recorded response body; binary bodies are base64 encoded
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// HARTimings are the phases of an exchange in milliseconds. Only the total is measured,
+// so it is reported as wait time.
+type HARTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+type harRecorderKey struct{}
+type harCommentKey struct{}
+
+// harRecorder collects the HTTP exchanges made under a test's context
+type harRecorder struct {
+	mu       sync.Mutex
+	entries  []HAREntry
+	redacted map[string]bool
+}
+
+// withHARRecorder returns a context whose HTTP exchanges are recorded by recorder
+func withHARRecorder(ctx context.Context, recorder *harRecorder) context.Context {
+	return context.WithValue(ctx, harRecorderKey{}, recorder)
+}
+
+// harRecorderFrom returns the recorder of ctx, or nil if its exchanges are not recorded
+func harRecorderFrom(ctx context.Context) *harRecorder {
+	recorder, _ := ctx.Value(harRecorderKey{}).(*harRecorder)
+	return recorder
+}
+
+// withHARComment labels the exchanges made under ctx, nesting within any existing label
+func withHARComment(ctx context.Context, comment string) context.Context {
+	if parent, ok := ctx.Value(harCommentKey{}).(string); ok && parent != "" {
+		comment = parent + " > " + comment
+	}
+	return context.WithValue(ctx, harCommentKey{}, comment)
+}
+
+// redactHARName marks a header or parameter name, such as a custom API key header, as secret
+// for the exchanges recorded under ctx
+func redactHARName(ctx context.Context, name string) {
+	recorder := harRecorderFrom(ctx)
+	if recorder == nil || name == "" {
+		return
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if recorder.redacted == nil {
+		recorder.redacted = make(map[string]bool)
+	}
+	recorder.redacted[strings.ToLower(name)] = true
+}
+
+// add records an entry
+func (r *harRecorder) add(entry HAREntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+}
+
+// isSecret reports whether the value of a header or parameter name must be redacted
+func (r *harRecorder) isSecret(name string) bool {
+	if isSecretName(name) {
+		return true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.redacted[strings.ToLower(name)]
+}
+
+// har returns the recorded exchanges ordered by start time, or nil if none were made
+func (r *harRecorder) har() *HAR {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) == 0 {
+		return nil
+	}
+
+	entries := append([]HAREntry(nil), r.entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedDateTime.Before(entries[j].StartedDateTime)
+	})
+	return &HAR{Log: HARLog{
+		Version: "1.2",
+		Creator: HARCreator{Name: "test-management-service", Version: "2.0"},
+		Entries: entries,
+	}}
+}
+
+// harTransport records exchanges whose request context carries a recorder
+type harTransport struct {
+	base http.RoundTripper
+}
+
+func (t *harTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder := harRecorderFrom(req.Context())
+	if recorder == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	comment, _ := req.Context().Value(harCommentKey{}).(string)
+	entry := HAREntry{
+		StartedDateTime: time.Now(),
+		Request:         recorder.request(req),
+		Comment:         comment,
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		entry.Time = msSince(entry.StartedDateTime)
+		entry.Timings.Wait = entry.Time
+		entry.Error = err.Error()
+		recorder.add(entry)
+		return nil, err
+	}
+
+	// Buffer the body so it can be both recorded and read by the caller
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	entry.Time = msSince(entry.StartedDateTime)
+	entry.Timings.Wait = entry.Time
+	entry.Response = recorder.response(resp, data)
+	if err != nil {
+		entry.Error = err.Error()
+		recorder.add(entry)
+		return nil, err
+	}
+	recorder.add(entry)
+	return resp, nil
+}
+
+// request converts a request to its HAR form with secrets redacted
+func (r *harRecorder) request(req *http.Request) HARRequest {
+	recorded := HARRequest{
+		Method:      req.Method,
+		URL:         r.redactURL(req.URL),
+		HTTPVersion: req.Proto,
+		Cookies:     []HARNameValue{},
+		Headers:     r.headers(req.Header),
+		QueryString: r.values(req.URL.Query()),
+		HeadersSize: -1,
+		BodySize:    int(req.ContentLength),
+	}
+	if req.Host != "" && req.Host != req.URL.Host {
+		recorded.Headers = append(recorded.Headers, HARNameValue{Name: "Host", Value: req.Host})
+	}
+
+	if req.GetBody == nil || req.ContentLength == 0 {
+		return recorded
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return recorded
+	}
+	data, _ := io.ReadAll(body)
+	body.Close()
+
+	contentType := req.Header.Get("Content-Type")
+	postData := &HARPostData{MimeType: contentType}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/x-www-form-urlencoded" {
+		if form, err := url.ParseQuery(string(data)); err == nil {
+			postData.Params = r.values(form)
+			redacted := url.Values{}
+			for _, param := range postData.Params {
+				redacted.Add(param.Name, param.Value)
+			}
+			data = []byte(redacted.Encode())
+		}
+	}
+	postData.Text, _, postData.Comment = harBodyText(r.redactJSON(data))
+	recorded.PostData = postData
+	recorded.BodySize = len(data)
+	return recorded
+}
+
+// response converts a response and its body to the HAR form with secrets redacted
+func (r *harRecorder) response(resp *http.Response, data []byte) HARResponse {
+	recorded := HARResponse{
+		Status:      resp.StatusCode,
+		StatusText:  strings.TrimPrefix(resp.Status, strconv.Itoa(resp.StatusCode)+" "),
+		HTTPVersion: resp.Proto,
+		Cookies:     []HARNameValue{},
+		Headers:     r.headers(resp.Header),
+		RedirectURL: resp.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    len(data),
+		Content: HARContent{
+			Size:     len(data),
+			MimeType: resp.Header.Get("Content-Type"),
+		},
+	}
+	recorded.Content.Text, recorded.Content.Encoding, recorded.Content.Comment = harBodyText(r.redactJSON(data))
+	return recorded
+}
+
+// headers converts headers to sorted name/value pairs with secrets redacted
+func (r *harRecorder) headers(header http.Header) []HARNameValue {
+	pairs := []HARNameValue{}
+	for name, values := range header {
+		for _, value := range values {
+			if r.isSecret(name) {
+				value = harRedacted
+			}
+			pairs = append(pairs, HARNameValue{Name: name, Value: value})
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].Name < pairs[j].Name })
+	return pairs
+}
+
+// values converts query or form parameters to sorted name/value pairs with secrets redacted
+func (r *harRecorder) values(values url.Values) []HARNameValue {
+	pairs := []HARNameValue{}
+	for _, name := range sortedValueKeys(values) {
+		for _, value := range values[name] {
+			if r.isSecret(name) {
+				value = harRedacted
+			}
+			pairs = append(pairs, HARNameValue{Name: name, Value: value})
+		}
+	}
+	return pairs
+}
+
+// redactURL returns the URL with secret query parameters and any password redacted
+func (r *harRecorder) redactURL(u *url.URL) string {
+	redacted := *u
+	if redacted.User != nil {
+		redacted.User = url.UserPassword(redacted.User.Username(), harRedacted)
+	}
+	if redacted.RawQuery != "" {
+		query := url.Values{}
+		for _, pair := range r.values(u.Query()) {
+			query.Add(pair.Name, pair.Value)
+		}
+		redacted.RawQuery = query.Encode()
+	}
+	return redacted.String()
+}
+
+// redactJSON returns a JSON object or array body with the values of secret keys redacted at any
+// depth, such as a login password or an OAuth2 access_token. Other bodies are returned unchanged.
+func (r *harRecorder) redactJSON(data []byte) []byte {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return data
+	}
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber()
+	var body interface{}
+	if err := decoder.Decode(&body); err != nil || decoder.More() {
+		return data
+	}
+	if !r.redactJSONValue(body) {
+		return data
+	}
+	redacted, err := json.Marshal(body)
+	if err != nil {
+		return data
+	}
+	return redacted
+}
+
+// redactJSONValue redacts the values of secret keys in a decoded JSON value in place and
+// reports whether any were found
+func (r *harRecorder) redactJSONValue(value interface{}) bool {
+	found := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if r.isSecret(key) {
+				v[key] = harRedacted
+				found = true
+			} else if r.redactJSONValue(item) {
+				found = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if r.redactJSONValue(item) {
+				found = true
+			}
+		}
+	}
+	return found
+}
+
+// harBodyText returns the body as HAR text, base64 encoding binary content and truncating large bodies
+func harBodyText(data []byte) (text, encoding, comment string) {
+	if len(data) > harMaxBodySize {
+		data = data[:harMaxBodySize]
+		comment = "truncated"
+	}
+	if utf8.Valid(data) {
+		return string(data), "", comment
+	}
+	return base64.StdEncoding.EncodeToString(data), "base64", comment
+}
+
+// sortedValueKeys returns the parameter names in sorted order
+func sortedValueKeys(values url.Values) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// msSince returns the milliseconds elapsed since start
+func msSince(start time.Time) float64 {
+	return float64(time.Since(start).Microseconds()) / 1000
+}
@@ -0,0 +1,193 @@
+package testcase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stepWorkflowExecutor runs each workflow as a single HTTP step through the unified executor
+type stepWorkflowExecutor struct {
+	executor *UnifiedTestExecutor
+}
+
+func (s *stepWorkflowExecutor) ExecuteContext(ctx context.Context, workflowID string, workflowDef interface{}) (*WorkflowResult, error) {
+	result := s.executor.ExecuteContext(ctx, &TestCase{
+		ID:   "step-1",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "GET", Path: "/step"},
+	})
+	return &WorkflowResult{RunID: "run-1", Status: "success", Error: result.Error}, nil
+}
+
+// harServer serves JSON, a binary payload and a session cookie
+func harServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "s-123"})
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"token": "t-1"}`))
+		case "/image":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte{0x89, 'P', 'N', 'G', 0xff, 0xfe})
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"path": "` + r.URL.Path + `"}`))
+		}
+	}))
+}
+
+// TestHAR_CapturesHooksAndTest tests that hooks and the main request are recorded in order with secrets redacted
+func TestHAR_CapturesHooksAndTest(t *testing.T) {
+	server := harServer()
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	result := executor.Execute(&TestCase{
+		ID:   "har-http",
+		Type: "http",
+		HTTP: &HTTPTest{
+			Method:  "POST",
+			Path:    "/orders?access_token=abc&page=2",
+			Headers: map[string]string{"Cookie": "session=s-123", "X-Trace": "trace-1"},
+			Body:    map[string]interface{}{"sku": "A-1"},
+			Auth:    &HTTPAuth{Type: "apikey", Key: "k-123", Name: "X-Tenant-Key"},
+		},
+		SetupHooks: []Hook{
+			{Type: "http", Name: "login", HTTP: &HTTPTest{Method: "POST", Path: "/login", Auth: &HTTPAuth{Type: "basic", Username: "alice", Password: "pw"}}},
+		},
+		TeardownHooks: []Hook{
+			{Type: "http", Name: "cleanup", HTTP: &HTTPTest{Method: "GET", Path: "/image"}},
+		},
+	})
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+	require.NotNil(t, result.HAR)
+
+	entries := result.HAR.Log.Entries
+	require.Len(t, entries, 3)
+	assert.Equal(t, "1.2", result.HAR.Log.Version)
+	assert.Equal(t, "setup hook login", entries[0].Comment)
+	assert.Equal(t, "test", entries[1].Comment)
+	assert.Equal(t, "teardown hook cleanup", entries[2].Comment)
+
+	headerValue := func(headers []HARNameValue, name string) string {
+		for _, header := range headers {
+			if header.Name == name {
+				return header.Value
+			}
+		}
+		return ""
+	}
+
+	// Credentials, cookies and secret query parameters are redacted
+	assert.Equal(t, harRedacted, headerValue(entries[0].Request.Headers, "Authorization"))
+	assert.Equal(t, harRedacted, headerValue(entries[0].Response.Headers, "Set-Cookie"))
+	assert.Equal(t, `{"token":"[REDACTED]"}`, entries[0].Response.Content.Text)
+
+	main := entries[1]
+	assert.Equal(t, "POST", main.Request.Method)
+	assert.Equal(t, harRedacted, headerValue(main.Request.Headers, "Cookie"))
+	assert.Equal(t, harRedacted, headerValue(main.Request.Headers, "X-Tenant-Key"))
+	assert.Equal(t, "trace-1", headerValue(main.Request.Headers, "X-Trace"))
+	assert.Equal(t, []HARNameValue{{Name: "access_token", Value: harRedacted}, {Name: "page", Value: "2"}}, main.Request.QueryString)
+	assert.NotContains(t, main.Request.URL, "abc")
+	require.NotNil(t, main.Request.PostData)
+	assert.Equal(t, `{"sku":"A-1"}`, main.Request.PostData.Text)
+	assert.Equal(t, 200, main.Response.Status)
+	assert.Equal(t, "OK", main.Response.StatusText)
+
+	// Binary bodies are base64 encoded
+	assert.Equal(t, "base64", entries[2].Response.Content.Encoding)
+	assert.Equal(t, "iVBOR//+", entries[2].Response.Content.Text)
+
+	// The archive is valid JSON with the fields HAR viewers require
+	data, err := json.Marshal(result.HAR)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"cache":{}`)
+}
+
+// TestHAR_WorkflowStepsAndFailures tests that workflow steps are recorded in the workflow test's archive
+// and that requests without a response are recorded with their error
+func TestHAR_WorkflowStepsAndFailures(t *testing.T) {
+	server := harServer()
+	defer server.Close()
+
+	workflows := &stepWorkflowExecutor{}
+	executor := NewUnifiedTestExecutor(server.URL, workflows, nil, nil)
+	workflows.executor = executor
+
+	result := executor.Execute(&TestCase{
+		ID:          "har-workflow",
+		Type:        "workflow",
+		WorkflowDef: map[string]interface{}{"name": "inline"},
+	})
+	assert.Equal(t, "passed", result.Status, "error: %s", result.Error)
+	require.NotNil(t, result.HAR)
+	require.Len(t, result.HAR.Log.Entries, 1)
+	assert.Equal(t, "test > step-1 > test", result.HAR.Log.Entries[0].Comment)
+	assert.Equal(t, `{"path": "/step"}`, result.HAR.Log.Entries[0].Response.Content.Text)
+
+	unreachable := NewExecutor("http://127.0.0.1:1")
+	result = unreachable.Execute(&TestCase{ID: "har-refused", Type: "http", HTTP: &HTTPTest{Method: "GET", Path: "/"}})
+	assert.Equal(t, "error", result.Status)
+	require.NotNil(t, result.HAR)
+	assert.Contains(t, result.HAR.Log.Entries[0].Error, "connection refused")
+
+	// Tests that make no HTTP requests have no archive
+	result = executor.Execute(&TestCase{ID: "har-none", Type: "command", Command: &CommandTest{Cmd: "true"}})
+	assert.Nil(t, result.HAR)
+}
+
+// TestHAR_OAuth2BodiesRedacted tests that secret keys in JSON bodies are redacted, including the
+// access token in the recorded token exchange of an oauth2 test
+func TestHAR_OAuth2BodiesRedacted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/oauth/token":
+			w.Write([]byte(`{"access_token": "at-secret", "refresh_token": "rt-secret", "expires_in": 3600, "scope": "orders"}`))
+		default:
+			w.Write([]byte(`{"user": {"name": "bob", "sessionId": "sid-9"}, "keys": [{"apiKey": "k-9", "label": "ci"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	result := executor.Execute(&TestCase{
+		ID:   "har-oauth2",
+		Type: "http",
+		HTTP: &HTTPTest{
+			Method: "POST",
+			Path:   "/login",
+			Body:   map[string]interface{}{"username": "bob", "password": "hunter2", "profile": map[string]interface{}{"secretAnswer": "blue"}},
+			Auth:   &HTTPAuth{Type: "oauth2", TokenURL: "/oauth/token", ClientID: "client-1", ClientSecret: "cs-secret"},
+		},
+	})
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+	require.NotNil(t, result.HAR)
+
+	entries := result.HAR.Log.Entries
+	require.Len(t, entries, 2)
+	token, login := entries[0], entries[1]
+	assert.Equal(t, server.URL+"/oauth/token", token.Request.URL)
+	assert.JSONEq(t, `{"access_token": "[REDACTED]", "refresh_token": "[REDACTED]", "expires_in": 3600, "scope": "orders"}`, token.Response.Content.Text)
+
+	require.NotNil(t, login.Request.PostData)
+	assert.JSONEq(t, `{"username": "bob", "password": "[REDACTED]", "profile": {"secretAnswer": "[REDACTED]"}}`, login.Request.PostData.Text)
+	assert.JSONEq(t, `{"user": {"name": "bob", "sessionId": "[REDACTED]"}, "keys": [{"apiKey": "[REDACTED]", "label": "ci"}]}`, login.Response.Content.Text)
+
+	data, err := json.Marshal(result.HAR)
+	require.NoError(t, err)
+	for _, secret := range []string{"at-secret", "rt-secret", "hunter2", "blue", "sid-9", "k-9", "cs-secret"} {
+		assert.NotContains(t, string(data), secret)
+	}
+
+	// The test still sees the real response
+	assert.Equal(t, "sid-9", result.Response["body"].(map[string]interface{})["user"].(map[string]interface{})["sessionId"])
+}
@@ -0,0 +1,316 @@
+package testcase
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+)
+
+// httpRequestBody is an encoded HTTP request body with its default Content-Type
+type httpRequestBody struct {
+	Data        []byte
+	ContentType string
+}
+
+// reader returns a reader over the body, or nil when there is no body
+func (b *httpRequestBody) reader() io.Reader {
+	if b == nil {
+		return nil
+	}
+	return bytes.NewReader(b.Data)
+}
+
+// encodeHTTPBody encodes the request body according to the configured body type.
+// It returns nil when the request has no body.
+func encodeHTTPBody(config *HTTPTest) (*httpRequestBody, error) {
+	switch strings.ToLower(config.BodyType) {
+	case "", "json":
+		if config.Body == nil {
+			return nil, nil
+		}
+		data, err := json.Marshal(config.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal body: %w", err)
+		}
+		return &httpRequestBody{Data: data, ContentType: "application/json"}, nil
+
+	case "raw":
+		return &httpRequestBody{Data: []byte(config.RawBody), ContentType: "text/plain; charset=utf-8"}, nil
+
+	case "form":
+		values := url.Values{}
+		for _, key := range sortedKeys(config.Body) {
+			for _, v := range formValues(config.Body[key]) {
+				values.Add(key, v)
+			}
+		}
+		return &httpRequestBody{Data: []byte(values.Encode()), ContentType: "application/x-www-form-urlencoded"}, nil
+
+	case "multipart":
+		return encodeMultipartBody(config)
+
+	case "binary":
+		var data []byte
+		var err error
+		if config.BodyFile != "" {
+			data, err = os.ReadFile(config.BodyFile)
+		} else {
+			data, err = base64.StdEncoding.DecodeString(config.RawBody)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load binary body: %w", err)
+		}
+		return &httpRequestBody{Data: data, ContentType: "application/octet-stream"}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported body type: %s", config.BodyType)
+	}
+}
+
+// encodeMultipartBody builds a multipart/form-data body from the body fields and file parts
+func encodeMultipartBody(config *HTTPTest) (*httpRequestBody, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	for _, key := range sortedKeys(config.Body) {
+		for _, v := range formValues(config.Body[key]) {
+			if err := writer.WriteField(key, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, file := range config.Files {
+		var data []byte
+		var err error
+		switch {
+		case file.Path != "":
+			data, err = os.ReadFile(file.Path)
+		case file.ContentBase64 != "":
+			data, err = base64.StdEncoding.DecodeString(file.ContentBase64)
+		default:
+			data = []byte(file.Content)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load file part %s: %w", file.Field, err)
+		}
+
+		filename := file.Filename
+		if filename == "" && file.Path != "" {
+			filename = filepath.Base(file.Path)
+		}
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+			"name":     file.Field,
+			"filename": filename,
+		}))
+		header.Set("Content-Type", contentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := part.Write(data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return &httpRequestBody{Data: buf.Bytes(), ContentType: writer.FormDataContentType()}, nil
+}
+
+// formValues converts a form field to its string values; arrays become repeated fields
+func formValues(value interface{}) []string {
+	if items, ok := value.([]interface{}); ok {
+		values := make([]string, len(items))
+		for i, item := range items {
+			values[i] = stringValue(item)
+		}
+		return values
+	}
+	return []string{stringValue(value)}
+}
+
+// sortedKeys returns map keys in a stable order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// setRequestHeaders sets the configured headers, adding the body's Content-Type unless one is configured
+func setRequestHeaders(req *http.Request, headers map[string]string, body *httpRequestBody) {
+	if body != nil && body.ContentType != "" {
+		req.Header.Set("Content-Type", body.ContentType)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+}
+
+// requestBodySummary describes the request body for the stored request snapshot
+func requestBodySummary(config *HTTPTest, body *httpRequestBody) interface{} {
+	switch strings.ToLower(config.BodyType) {
+	case "raw":
+		return config.RawBody
+	case "binary":
+		return fmt.Sprintf("<%d bytes>", len(body.Data))
+	case "multipart":
+		files := make([]string, len(config.Files))
+		for i, file := range config.Files {
+			files[i] = file.Field
+		}
+		return map[string]interface{}{"fields": config.Body, "files": files}
+	default:
+		return config.Body
+	}
+}
+
+// httpResponseBody is a response body decoded according to its kind
+type httpResponseBody struct {
+	Kind string      // json, xml, text, binary
+	Raw  []byte      // undecoded body
+	Data interface{} // parsed JSON value, or the text of text bodies
+	doc  *xmlquery.Node
+}
+
+// decodeResponseBody determines the body kind from the configured response type or the
+// Content-Type header, falling back to sniffing, and decodes JSON and text bodies
+func decodeResponseBody(responseType, contentType string, raw []byte) *httpResponseBody {
+	body := &httpResponseBody{Kind: strings.ToLower(responseType), Raw: raw}
+	if body.Kind == "" || body.Kind == "auto" {
+		body.Kind = responseKind(contentType, raw)
+	}
+
+	switch body.Kind {
+	case "json":
+		json.Unmarshal(raw, &body.Data)
+	case "text":
+		body.Data = string(raw)
+	}
+	return body
+}
+
+// responseKind classifies a response body by media type, sniffing the content when the type is not conclusive
+func responseKind(contentType string, raw []byte) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return "json"
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return "xml"
+	case len(raw) == 0 || json.Valid(raw):
+		// Many servers send JSON as text/plain or without a Content-Type
+		return "json"
+	case strings.HasPrefix(mediaType, "text/"):
+		return "text"
+	case mediaType != "" && mediaType != "application/octet-stream":
+		return "binary"
+	case utf8.Valid(raw):
+		return "text"
+	default:
+		return "binary"
+	}
+}
+
+// sha256 returns the hex-encoded SHA-256 digest of the body
+func (b *httpResponseBody) sha256() string {
+	sum := sha256.Sum256(b.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// snapshotSubject returns the value compared by snapshot assertions
+func (b *httpResponseBody) snapshotSubject() interface{} {
+	switch {
+	case b.Kind == "json" && (b.Data != nil || len(b.Raw) == 0):
+		return b.Data
+	case b.Kind == "binary":
+		return map[string]interface{}{"size": len(b.Raw), "sha256": b.sha256()}
+	default:
+		return string(b.Raw)
+	}
+}
+
+// responseFields returns the body fields stored in the test result's response
+func (b *httpResponseBody) responseFields() map[string]interface{} {
+	fields := map[string]interface{}{"bodyType": b.Kind}
+	if b.Kind == "binary" {
+		fields["bodySize"] = len(b.Raw)
+		fields["bodySha256"] = b.sha256()
+		return fields
+	}
+	fields["body"] = b.Data
+	fields["bodyRaw"] = string(b.Raw)
+	return fields
+}
+
+// checkXPath evaluates an XPath expression against an XML body.
+// Node sets yield the text of the first node, or the node count for the length operator;
+// expressions such as count() or boolean tests yield their value directly.
+func checkXPath(assertion Assertion, body *httpResponseBody) (interface{}, string) {
+	subject := "XPath " + assertion.Path
+	if body.doc == nil {
+		doc, err := xmlquery.Parse(bytes.NewReader(body.Raw))
+		if err != nil {
+			return nil, fmt.Sprintf("%s: response is not valid XML: %v", subject, err)
+		}
+		body.doc = doc
+	}
+
+	expr, err := xpath.Compile(assertion.Path)
+	if err != nil {
+		return nil, fmt.Sprintf("%s: invalid expression: %v", subject, err)
+	}
+
+	switch v := expr.Evaluate(xmlquery.CreateXPathNavigator(body.doc)).(type) {
+	case *xpath.NodeIterator:
+		var texts []interface{}
+		for v.MoveNext() {
+			texts = append(texts, v.Current().Value())
+		}
+		if normalizeOperator(assertion.Operator, "equals") == "length" {
+			return len(texts), checkOperator(assertion, subject, texts, true, "equals")
+		}
+		if len(texts) == 0 {
+			return nil, checkOperator(assertion, subject, nil, false, "equals")
+		}
+		return texts[0], checkOperator(assertion, subject, texts[0], true, "equals")
+	default:
+		return v, checkOperator(assertion, subject, v, true, "equals")
+	}
+}
+
+// checkBodySHA256 compares the body digest with an expected hex digest, ignoring case
+func checkBodySHA256(assertion Assertion, body *httpResponseBody) (interface{}, string) {
+	if expected, ok := assertion.Expected.(string); ok {
+		assertion.Expected = strings.ToLower(expected)
+	}
+	digest := body.sha256()
+	return digest, checkOperator(assertion, "body sha256", digest, true, "equals")
+}
@@ -0,0 +1,179 @@
+package testcase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHTTPBody_FormAndMultipart tests form-urlencoded and multipart requests with file parts
+func TestHTTPBody_FormAndMultipart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		echo := map[string]interface{}{"contentType": r.Header.Get("Content-Type")}
+		switch r.URL.Path {
+		case "/form":
+			r.ParseForm()
+			echo["user"] = r.PostForm.Get("user")
+			echo["tags"] = r.PostForm["tags"]
+		case "/upload":
+			require.NoError(t, r.ParseMultipartForm(1<<20))
+			echo["title"] = r.FormValue("title")
+			var files []map[string]interface{}
+			for field, headers := range r.MultipartForm.File {
+				for _, h := range headers {
+					f, _ := h.Open()
+					data, _ := io.ReadAll(f)
+					f.Close()
+					files = append(files, map[string]interface{}{
+						"field": field, "filename": h.Filename, "content": string(data),
+						"contentType": h.Header.Get("Content-Type"),
+					})
+				}
+			}
+			echo["files"] = files
+		}
+		json.NewEncoder(w).Encode(echo)
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	result := executor.Execute(&TestCase{
+		ID:   "body-form",
+		Type: "http",
+		HTTP: &HTTPTest{
+			Method:   "POST",
+			Path:     "/form",
+			BodyType: "form",
+			Body:     map[string]interface{}{"user": "alice", "tags": []interface{}{"a", "b"}},
+		},
+		Assertions: []Assertion{
+			{Type: "json_path", Path: "$.contentType", Expected: "application/x-www-form-urlencoded"},
+			{Type: "json_path", Path: "$.user", Expected: "alice"},
+			{Type: "json_path", Path: "$.tags", Expected: []interface{}{"a", "b"}},
+		},
+	})
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+
+	upload := filepath.Join(t.TempDir(), "report.csv")
+	require.NoError(t, os.WriteFile(upload, []byte("id,name\n1,a\n"), 0o644))
+
+	result = executor.Execute(&TestCase{
+		ID:   "body-multipart",
+		Type: "http",
+		HTTP: &HTTPTest{
+			Method:   "POST",
+			Path:     "/upload",
+			BodyType: "multipart",
+			Body:     map[string]interface{}{"title": "monthly"},
+			Files: []HTTPFilePart{
+				{Field: "report", Path: upload, ContentType: "text/csv"},
+			},
+		},
+		Assertions: []Assertion{
+			{Type: "json_path", Path: "$.contentType", Operator: "starts_with", Expected: "multipart/form-data; boundary="},
+			{Type: "json_path", Path: "$.title", Expected: "monthly"},
+			{Type: "json_path", Path: "$.files[0].filename", Expected: "report.csv"},
+			{Type: "json_path", Path: "$.files[0].contentType", Expected: "text/csv"},
+			{Type: "json_path", Path: "$.files[0].content", Expected: "id,name\n1,a\n"},
+		},
+	})
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+}
+
+// TestHTTPBody_RawXMLRequestWithXPath tests a SOAP-style raw request and XPath assertions
+func TestHTTPBody_RawXMLRequestWithXPath(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		received = string(data)
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		w.Write([]byte(`<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <GetUsersResponse>
+      <user id="1"><name>alice</name></user>
+      <user id="2"><name>bob</name></user>
+    </GetUsersResponse>
+  </soap:Body>
+</soap:Envelope>`))
+	}))
+	defer server.Close()
+
+	request := `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><GetUsers/></soap:Body></soap:Envelope>`
+	executor := NewExecutor(server.URL)
+	result := executor.Execute(&TestCase{
+		ID:   "body-soap",
+		Type: "http",
+		HTTP: &HTTPTest{
+			Method:   "POST",
+			Path:     "/soap",
+			Headers:  map[string]string{"Content-Type": "text/xml"},
+			BodyType: "raw",
+			RawBody:  request,
+		},
+		Assertions: []Assertion{
+			{Type: "xpath", Path: "//user[@id='2']/name", Expected: "bob"},
+			{Type: "xpath", Path: "//user", Operator: "length", Expected: 2},
+			{Type: "xpath", Path: "count(//user)", Expected: 2},
+			{Type: "xpath", Path: "//order", Operator: "not_exists"},
+			{Type: "body", Operator: "regex", Expected: `<name>alice</name>`},
+		},
+	})
+
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+	assert.Equal(t, request, received)
+	assert.Equal(t, "xml", result.Response["bodyType"])
+}
+
+// TestHTTPBody_BinaryRequestAndResponse tests binary payloads with size and sha256 assertions
+func TestHTTPBody_BinaryRequestAndResponse(t *testing.T) {
+	payload := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff, 0x10}
+	sum := sha256.Sum256(payload)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	result := executor.Execute(&TestCase{
+		ID:   "body-binary",
+		Type: "http",
+		HTTP: &HTTPTest{
+			Method:   "PUT",
+			Path:     "/image",
+			BodyType: "binary",
+			RawBody:  "iVBORwD/EA==",
+		},
+		Assertions: []Assertion{
+			{Type: "body_size", Expected: len(payload)},
+			{Type: "body_sha256", Expected: hex.EncodeToString(sum[:])},
+		},
+	})
+
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+	assert.Equal(t, "binary", result.Response["bodyType"])
+	assert.NotContains(t, result.Response, "bodyRaw")
+}
+
+// TestResponseKind tests body classification by Content-Type and content sniffing
+func TestResponseKind(t *testing.T) {
+	assert.Equal(t, "json", responseKind("application/problem+json", []byte(`{}`)))
+	assert.Equal(t, "json", responseKind("text/plain; charset=utf-8", []byte(`{"a": 1}`)))
+	assert.Equal(t, "xml", responseKind("application/soap+xml", []byte(`<a/>`)))
+	assert.Equal(t, "text", responseKind("text/html", []byte(`<html></html>`)))
+	assert.Equal(t, "binary", responseKind("application/pdf", []byte(`%PDF`)))
+	assert.Equal(t, "text", responseKind("", []byte(`plain words`)))
+	assert.Equal(t, "binary", responseKind("", []byte{0xff, 0xfe, 0x00}))
+}
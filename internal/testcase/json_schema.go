@@ -0,0 +1,104 @@
+package testcase
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// inlineSchemaURL is the resource URL inline and stored schemas are compiled under
+const inlineSchemaURL = "urn:nextest:schema"
+
+// CompileJSONSchema compiles a schema document, defaulting to draft 2020-12 when it declares no $schema
+func CompileJSONSchema(doc interface{}) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+	if err := compiler.AddResource(inlineSchemaURL, normalizeJSON(doc)); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(inlineSchemaURL)
+}
+
+// checkJSONSchema validates the body (or the value at Path) against an inline schema
+// or a stored schema referenced by ID, returning one failure per violation
+func (e *UnifiedTestExecutor) checkJSONSchema(assertion Assertion, body interface{}) []string {
+	instance := body
+	location := "$"
+	if assertion.Path != "" {
+		value, found, err := LookupJSONPath(body, assertion.Path)
+		if err != nil {
+			return []string{err.Error()}
+		}
+		if !found {
+			return []string{fmt.Sprintf("json_schema: JSON path %s not found", assertion.Path)}
+		}
+		instance, location = value, assertion.Path
+	}
+
+	doc, err := e.resolveJSONSchema(assertion.Expected)
+	if err != nil {
+		return []string{fmt.Sprintf("json_schema: %v", err)}
+	}
+
+	schema, err := CompileJSONSchema(doc)
+	if err != nil {
+		return []string{fmt.Sprintf("json_schema: invalid schema: %v", err)}
+	}
+
+	err = schema.Validate(normalizeJSON(instance))
+	if err == nil {
+		return nil
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return []string{fmt.Sprintf("json_schema: %v", err)}
+	}
+	var failures []string
+	for _, violation := range schemaViolations(validationErr) {
+		failures = append(failures, fmt.Sprintf("json_schema: %s%s: %s", location, violation[0], violation[1]))
+	}
+	return failures
+}
+
+// resolveJSONSchema returns an inline schema document, or loads a stored schema when expected is a schema ID
+func (e *UnifiedTestExecutor) resolveJSONSchema(expected interface{}) (interface{}, error) {
+	switch v := expected.(type) {
+	case map[string]interface{}, bool:
+		return v, nil
+	case string:
+		if e.schemaRepo == nil {
+			return nil, fmt.Errorf("schema repository not configured, cannot load schema %s", v)
+		}
+		stored, err := e.schemaRepo.GetSchema(v)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}(stored.Schema), nil
+	default:
+		return nil, fmt.Errorf("expected must be an inline schema object or a schema ID, got %T", expected)
+	}
+}
+
+// schemaViolations flattens a validation error into (instance pointer, message) pairs, one per leaf violation
+func schemaViolations(err *jsonschema.ValidationError) [][2]string {
+	var violations [][2]string
+	var walk func(unit *jsonschema.OutputUnit)
+	walk = func(unit *jsonschema.OutputUnit) {
+		if len(unit.Errors) == 0 && unit.Error != nil {
+			violations = append(violations, [2]string{unit.InstanceLocation, unit.Error.String()})
+		}
+		for i := range unit.Errors {
+			walk(&unit.Errors[i])
+		}
+	}
+	walk(err.BasicOutput())
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		return strings.Compare(violations[i][0], violations[j][0]) < 0
+	})
+	return violations
+}
@@ -0,0 +1,107 @@
+package testcase
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"test-management-service/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSchemaRepository serves stored schemas from memory
+type fakeSchemaRepository map[string]models.JSONB
+
+func (r fakeSchemaRepository) GetSchema(schemaID string) (*models.JSONSchema, error) {
+	schema, ok := r[schemaID]
+	if !ok {
+		return nil, fmt.Errorf("schema not found: %s", schemaID)
+	}
+	return &models.JSONSchema{SchemaID: schemaID, Schema: schema}, nil
+}
+
+func newSchemaTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"total": 2, "items": [{"id": 1, "name": "a"}, {"id": "2"}]}`))
+	}))
+}
+
+var itemListSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []interface{}{"total", "items"},
+	"properties": map[string]interface{}{
+		"total": map[string]interface{}{"type": "integer"},
+		"items": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":     "object",
+				"required": []interface{}{"id", "name"},
+				"properties": map[string]interface{}{
+					"id":   map[string]interface{}{"type": "integer"},
+					"name": map[string]interface{}{"type": "string"},
+				},
+			},
+		},
+	},
+}
+
+// TestJSONSchema_InlineViolations tests that each violation is reported with its instance path
+func TestJSONSchema_InlineViolations(t *testing.T) {
+	server := newSchemaTestServer()
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	result := executor.Execute(&TestCase{
+		ID:   "schema-inline",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "GET", Path: "/items"},
+		Assertions: []Assertion{
+			{Type: "json_schema", Expected: itemListSchema},
+		},
+	})
+
+	assert.Equal(t, "failed", result.Status)
+	if assert.Len(t, result.Failures, 2, "failures: %v", result.Failures) {
+		assert.Contains(t, result.Failures[0], "json_schema: $/items/1")
+		assert.Contains(t, result.Failures[1], "json_schema: $/items/1/id")
+	}
+}
+
+// TestJSONSchema_PathAndStoredSchema tests validating a sub-document against a stored schema
+func TestJSONSchema_PathAndStoredSchema(t *testing.T) {
+	server := newSchemaTestServer()
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	executor.SetSchemaRepository(fakeSchemaRepository{
+		"item": models.JSONB{
+			"type":     "object",
+			"required": []interface{}{"id"},
+		},
+	})
+
+	result := executor.Execute(&TestCase{
+		ID:   "schema-stored",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "GET", Path: "/items"},
+		Assertions: []Assertion{
+			{Type: "json_schema", Path: "$.items[0]", Expected: "item"},
+			{Type: "json_schema", Path: "total", Expected: map[string]interface{}{"minimum": 1}},
+		},
+	})
+	assert.Equal(t, "passed", result.Status, "failures: %v", result.Failures)
+
+	result = executor.Execute(&TestCase{
+		ID:   "schema-missing",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "GET", Path: "/items"},
+		Assertions: []Assertion{
+			{Type: "json_schema", Expected: "unknown"},
+		},
+	})
+	assert.Equal(t, "failed", result.Status)
+	assert.Equal(t, []string{"json_schema: schema not found: unknown"}, result.Failures)
+}
@@ -0,0 +1,169 @@
+package testcase
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/ohler55/ojg/jp"
+)
+
+// jsonPathCache holds parsed JSONPath expressions keyed by their normalized source
+var jsonPathCache sync.Map
+
+// parseJSONPath parses a JSONPath expression; paths without a leading "$" are taken relative to the root
+func parseJSONPath(path string) (jp.Expr, error) {
+	path = strings.TrimSpace(path)
+	switch {
+	case path == "":
+		path = "$"
+	case strings.HasPrefix(path, "$"):
+	case strings.HasPrefix(path, "["):
+		path = "$" + path
+	default:
+		path = "$." + path
+	}
+
+	if cached, ok := jsonPathCache.Load(path); ok {
+		return cached.(jp.Expr), nil
+	}
+	expr, err := jp.ParseString(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON path %s: %w", path, err)
+	}
+	jsonPathCache.Store(path, expr)
+	return expr, nil
+}
+
+// isDefiniteJSONPath reports whether an expression can match at most one value
+func isDefiniteJSONPath(expr jp.Expr) bool {
+	for _, frag := range expr {
+		switch frag.(type) {
+		case jp.Root, jp.At, jp.Bracket, jp.Child, jp.Nth:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// EvaluateJSONPath returns every value matched by a JSONPath expression
+func EvaluateJSONPath(data interface{}, path string) ([]interface{}, error) {
+	expr, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return expr.Get(normalizeJSON(data)), nil
+}
+
+// LookupJSONPath resolves a JSONPath expression to a single value.
+// Definite paths ($.a.b[0]) yield the matched value; wildcards, filters, slices
+// and recursive descent yield the list of all matches. found is false when nothing matched.
+func LookupJSONPath(data interface{}, path string) (value interface{}, found bool, err error) {
+	expr, err := parseJSONPath(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	matches := expr.Get(normalizeJSON(data))
+	if isDefiniteJSONPath(expr) {
+		if len(matches) == 0 {
+			return nil, false, nil
+		}
+		return matches[0], true, nil
+	}
+	if matches == nil {
+		matches = []interface{}{}
+	}
+	return matches, len(matches) > 0, nil
+}
+
+// normalizeJSON converts data to the generic form produced by encoding/json
+// (map[string]interface{}, []interface{}, float64, string, bool, nil)
+func normalizeJSON(data interface{}) interface{} {
+	switch v := data.(type) {
+	case nil, string, bool, float64:
+		return v
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[k] = normalizeJSON(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = normalizeJSON(item)
+		}
+		return out
+	}
+
+	if f, ok := toFloat(data); ok {
+		return f
+	}
+
+	// Typed values (structs, typed maps and slices) go through a JSON round trip
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+	var out interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return data
+	}
+	return out
+}
+
+// jsonEqual compares an expected value with an actual JSON value.
+// When either side is a number, the other side is coerced (numeric strings included) and compared numerically.
+func jsonEqual(expected, actual interface{}) bool {
+	_, expectedIsNumber := toFloat(expected)
+	_, actualIsNumber := toFloat(actual)
+	if expectedIsNumber || actualIsNumber {
+		ef, okE := toNumber(expected)
+		af, okA := toNumber(actual)
+		return okE && okA && ef == af
+	}
+	return reflect.DeepEqual(normalizeJSON(expected), normalizeJSON(actual))
+}
+
+// jsonLength returns the length of a string, array or object
+func jsonLength(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case string:
+		return utf8.RuneCountInString(v), true
+	case []interface{}:
+		return len(v), true
+	case map[string]interface{}:
+		return len(v), true
+	default:
+		return 0, false
+	}
+}
+
+// jsonContains reports whether a string contains a substring, an array contains an element, or an object has a key
+func jsonContains(value, expected interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.Contains(v, fmt.Sprint(expected))
+	case []interface{}:
+		for _, item := range v {
+			if jsonEqual(expected, item) {
+				return true
+			}
+		}
+		return false
+	case map[string]interface{}:
+		key, ok := expected.(string)
+		if !ok {
+			return false
+		}
+		_, exists := v[key]
+		return exists
+	default:
+		return false
+	}
+}
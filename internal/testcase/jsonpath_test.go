@@ -0,0 +1,124 @@
+package testcase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ordersJSON = `{
+	"store": {"name": "main", "owner": {"name": "ops"}},
+	"items": [
+		{"id": 1, "status": "active", "price": 12.5, "tags": ["new"]},
+		{"id": 2, "status": "closed", "price": 3},
+		{"id": 3, "status": "active", "price": 40}
+	]
+}`
+
+// TestLookupJSONPath tests indices, wildcards, filters and recursive descent
+func TestLookupJSONPath(t *testing.T) {
+	var data interface{}
+	require.NoError(t, json.Unmarshal([]byte(ordersJSON), &data))
+
+	tests := []struct {
+		path     string
+		expected interface{}
+		found    bool
+	}{
+		{"$.store.name", "main", true},
+		{"store.owner.name", "ops", true},
+		{"$.items[1].id", float64(2), true},
+		{"$.items[-1].id", float64(3), true},
+		{"$.items[*].id", []interface{}{float64(1), float64(2), float64(3)}, true},
+		{"$.items[?(@.status=='active')].id", []interface{}{float64(1), float64(3)}, true},
+		{"$.items[?(@.price > 10)].id", []interface{}{float64(1), float64(3)}, true},
+		{"$..name", []interface{}{"main", "ops"}, true},
+		{"$.items[0:2].status", []interface{}{"active", "closed"}, true},
+		{"$.items[5].id", nil, false},
+		{"$.items[?(@.status=='missing')]", []interface{}{}, false},
+	}
+
+	for _, tt := range tests {
+		value, found, err := LookupJSONPath(data, tt.path)
+		require.NoError(t, err, tt.path)
+		assert.Equal(t, tt.found, found, tt.path)
+		if tt.found {
+			if list, ok := tt.expected.([]interface{}); ok {
+				assert.ElementsMatch(t, list, value, tt.path)
+			} else {
+				assert.Equal(t, tt.expected, value, tt.path)
+			}
+		}
+	}
+
+	_, _, err := LookupJSONPath(data, "$.items[?(@.status==")
+	assert.Error(t, err)
+}
+
+// TestHTTP_JSONPathTopLevelArray tests array operators against a top-level array body
+func TestHTTP_JSONPathTopLevelArray(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":"a","status":"active"},{"id":"b","status":"active","tags":["x","y"]}]`))
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	result := executor.Execute(&TestCase{
+		ID:   "jsonpath-array",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "GET", Path: "/orders"},
+		Assertions: []Assertion{
+			{Type: "json_path", Path: "$", Operator: "length", Expected: float64(2)},
+			{Type: "json_path", Path: "$[0].id", Expected: "a"},
+			{Type: "json_path", Path: "$[*].status", Operator: "all", Expected: "active"},
+			{Type: "json_path", Path: "$[*].id", Operator: "contains", Expected: "b"},
+			{Type: "json_path", Path: "$[1].tags", Expected: []interface{}{"x", "y"}},
+			{Type: "json_path", Path: "$[0].tags", Operator: "not_exists"},
+		},
+	})
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+
+	result = executor.Execute(&TestCase{
+		ID:   "jsonpath-array-fail",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "GET", Path: "/orders"},
+		Assertions: []Assertion{
+			{Type: "json_path", Path: "$[*].id", Operator: "all", Expected: "a"},
+			{Type: "json_path", Path: "$[?(@.status=='closed')]", Operator: "exists"},
+		},
+	})
+	assert.Equal(t, "failed", result.Status)
+	assert.Len(t, result.Failures, 2)
+}
+
+// TestHook_ExtractJSONPath tests extracting hook response values into the context
+func TestHook_ExtractJSONPath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"token":"abc","users":[{"id":7},{"id":9}]}}`))
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	ctx := make(map[string]interface{})
+	hook := &Hook{
+		Type: "http",
+		Name: "login",
+		HTTP: &HTTPTest{Method: "POST", Path: "/login"},
+		Extract: map[string]string{
+			"token":   "$.body.data.token",
+			"userIds": "$.body.data.users[*].id",
+		},
+	}
+
+	assert.True(t, executor.executeHook(context.Background(), hook, "setup", &TestResult{}, ctx))
+	assert.Equal(t, "abc", ctx["token"])
+	assert.Equal(t, []interface{}{float64(7), float64(9)}, ctx["userIds"])
+
+	hook.Extract = map[string]string{"missing": "$.body.data.nothing"}
+	assert.False(t, executor.executeHook(context.Background(), hook, "setup", &TestResult{}, ctx))
+}
@@ -0,0 +1,271 @@
+package testcase
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// operatorAliases maps alternative spellings to canonical operator names
+var operatorAliases = map[string]string{
+	"":        "equals",
+	"eq":      "equals",
+	"==":      "equals",
+	"ne":      "not_equals",
+	"!=":      "not_equals",
+	">":       "gt",
+	">=":      "gte",
+	"<":       "lt",
+	"<=":      "lte",
+	"in":      "one_of",
+	"matches": "regex",
+}
+
+// normalizeOperator returns the canonical operator name, using def when the assertion has none
+func normalizeOperator(operator, def string) string {
+	op := strings.ToLower(strings.TrimSpace(operator))
+	if op == "" {
+		op = def
+	}
+	if alias, ok := operatorAliases[op]; ok {
+		return alias
+	}
+	return op
+}
+
+// checkOperator applies the assertion's operator to an actual value.
+// subject names the checked value in failure messages; found is false when the value does not exist.
+// It returns an empty string when the check holds, otherwise a failure message.
+func checkOperator(assertion Assertion, subject string, actual interface{}, found bool, defaultOperator string) string {
+	op := normalizeOperator(assertion.Operator, defaultOperator)
+	expected := assertion.Expected
+
+	switch op {
+	case "exists":
+		if !found {
+			return fmt.Sprintf("%s should exist", subject)
+		}
+		return ""
+	case "not_exists":
+		if found {
+			return fmt.Sprintf("%s should not exist, got %s", subject, describeValue(actual))
+		}
+		return ""
+	}
+
+	if !found {
+		return fmt.Sprintf("%s not found", subject)
+	}
+
+	ok, err := evaluateOperator(op, actual, expected)
+	if err != nil {
+		return fmt.Sprintf("%s: %v", subject, err)
+	}
+	if ok {
+		return ""
+	}
+
+	switch op {
+	case "equals":
+		return fmt.Sprintf("%s: expected %v, got %s", subject, expected, describeValue(actual))
+	case "length":
+		n, _ := jsonLength(normalizeJSON(actual))
+		return fmt.Sprintf("%s: expected length %v, got %d", subject, expected, n)
+	default:
+		return fmt.Sprintf("%s: expected %s %v, got %s", subject, op, expected, describeValue(actual))
+	}
+}
+
+// maxDescribedLength caps how much of an actual value is quoted in failure messages
+const maxDescribedLength = 200
+
+// describeValue renders an actual value for failure messages, truncating long output
+func describeValue(v interface{}) string {
+	s := fmt.Sprint(v)
+	if len(s) > maxDescribedLength {
+		return s[:maxDescribedLength] + "..."
+	}
+	return s
+}
+
+// evaluateOperator reports whether actual satisfies operator op against expected.
+// Unknown operators and malformed expected values are errors, never passes.
+func evaluateOperator(op string, actual, expected interface{}) (bool, error) {
+	switch op {
+	case "equals":
+		return jsonEqual(expected, actual), nil
+
+	case "not_equals":
+		return !jsonEqual(expected, actual), nil
+
+	case "gt", "gte", "lt", "lte":
+		a, ok := toNumber(actual)
+		if !ok {
+			return false, fmt.Errorf("%v is not a number", actual)
+		}
+		e, ok := toNumber(expected)
+		if !ok {
+			return false, fmt.Errorf("expected value %v is not a number", expected)
+		}
+		switch op {
+		case "gt":
+			return a > e, nil
+		case "gte":
+			return a >= e, nil
+		case "lt":
+			return a < e, nil
+		default:
+			return a <= e, nil
+		}
+
+	case "between":
+		bounds, ok := expected.([]interface{})
+		if !ok || len(bounds) != 2 {
+			return false, fmt.Errorf("between expects [min, max], got %v", expected)
+		}
+		lo, okLo := toNumber(bounds[0])
+		hi, okHi := toNumber(bounds[1])
+		if !okLo || !okHi {
+			return false, fmt.Errorf("between bounds must be numbers, got %v", expected)
+		}
+		a, ok := toNumber(actual)
+		if !ok {
+			return false, fmt.Errorf("%v is not a number", actual)
+		}
+		return a >= lo && a <= hi, nil
+
+	case "one_of":
+		options, ok := expected.([]interface{})
+		if !ok {
+			return false, fmt.Errorf("one_of expects an array, got %v", expected)
+		}
+		for _, option := range options {
+			if jsonEqual(option, actual) {
+				return true, nil
+			}
+		}
+		return false, nil
+
+	case "regex":
+		pattern, ok := expected.(string)
+		if !ok {
+			return false, fmt.Errorf("regex expects a pattern string, got %v", expected)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, fmt.Errorf("invalid regex %q: %v", pattern, err)
+		}
+		return re.MatchString(stringValue(actual)), nil
+
+	case "contains":
+		return jsonContains(normalizeJSON(actual), expected), nil
+
+	case "not_contains":
+		return !jsonContains(normalizeJSON(actual), expected), nil
+
+	case "starts_with":
+		return strings.HasPrefix(stringValue(actual), fmt.Sprint(expected)), nil
+
+	case "ends_with":
+		return strings.HasSuffix(stringValue(actual), fmt.Sprint(expected)), nil
+
+	case "length":
+		n, ok := jsonLength(normalizeJSON(actual))
+		if !ok {
+			return false, fmt.Errorf("%v has no length", actual)
+		}
+		if _, ok := toNumber(expected); !ok {
+			return false, fmt.Errorf("expected length %v is not a number", expected)
+		}
+		return jsonEqual(expected, n), nil
+
+	case "type_is":
+		name, ok := expected.(string)
+		if !ok {
+			return false, fmt.Errorf("type_is expects a type name, got %v", expected)
+		}
+		return typeMatches(strings.ToLower(name), normalizeJSON(actual))
+
+	case "all":
+		// Every element (or the single value) must equal the expected value
+		items, ok := normalizeJSON(actual).([]interface{})
+		if !ok {
+			items = []interface{}{actual}
+		}
+		for _, item := range items {
+			if !jsonEqual(expected, item) {
+				return false, nil
+			}
+		}
+		return true, nil
+
+	default:
+		return false, fmt.Errorf("unsupported operator: %s", op)
+	}
+}
+
+// typeMatches checks a normalized JSON value against a type name
+func typeMatches(name string, value interface{}) (bool, error) {
+	actual := jsonTypeName(value)
+	switch name {
+	case "string", "number", "boolean", "array", "object", "null":
+		return actual == name, nil
+	case "bool":
+		return actual == "boolean", nil
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f), nil
+	default:
+		return false, fmt.Errorf("unknown type %s (string, number, integer, boolean, array, object, null)", name)
+	}
+}
+
+// jsonTypeName returns the JSON type of a normalized value
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// toNumber converts numbers and numeric strings to float64
+func toNumber(v interface{}) (float64, bool) {
+	if f, ok := toFloat(v); ok {
+		return f, true
+	}
+	switch s := v.(type) {
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		return f, err == nil
+	case json.Number:
+		f, err := s.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// stringValue renders a value for string operators; strings are used as-is
+func stringValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,103 @@
+package testcase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEvaluateOperator tests each operator, including numeric coercion
+func TestEvaluateOperator(t *testing.T) {
+	tests := []struct {
+		op       string
+		actual   interface{}
+		expected interface{}
+		want     bool
+	}{
+		{"equals", float64(200), 200, true},
+		{"equals", "42", float64(42), true},
+		{"equals", "007", "7", false},
+		{"equals", map[string]interface{}{"a": 1}, map[string]interface{}{"a": float64(1)}, true},
+		{"not_equals", "active", "closed", true},
+		{"gt", 5, float64(3), true},
+		{"gte", "3", 3, true},
+		{"lt", int64(12), 10, false},
+		{"lte", 10.0, "10", true},
+		{"between", 250, []interface{}{float64(200), float64(299)}, true},
+		{"between", 404, []interface{}{float64(200), float64(299)}, false},
+		{"one_of", 201, []interface{}{float64(200), float64(201)}, true},
+		{"one_of", "pending", []interface{}{"active", "closed"}, false},
+		{"regex", "order-1234", `^order-\d+$`, true},
+		{"contains", []interface{}{"a", "b"}, "b", true},
+		{"contains", "hello world", "world", true},
+		{"not_contains", "hello world", "bye", true},
+		{"starts_with", "Bearer abc", "Bearer ", true},
+		{"ends_with", "report.csv", ".json", false},
+		{"length", []interface{}{1, 2, 3}, float64(3), true},
+		{"length", "héllo", 5, true},
+		{"type_is", []interface{}{}, "array", true},
+		{"type_is", float64(3), "integer", true},
+		{"type_is", 3.5, "integer", false},
+		{"type_is", nil, "null", true},
+		{"all", []interface{}{"x", "x"}, "x", true},
+		{"all", []interface{}{"x", "y"}, "x", false},
+	}
+
+	for _, tt := range tests {
+		got, err := evaluateOperator(tt.op, tt.actual, tt.expected)
+		assert.NoError(t, err, "%s %v %v", tt.op, tt.actual, tt.expected)
+		assert.Equal(t, tt.want, got, "%s %v %v", tt.op, tt.actual, tt.expected)
+	}
+}
+
+// TestEvaluateOperator_Errors tests that unknown operators and malformed expectations never pass
+func TestEvaluateOperator_Errors(t *testing.T) {
+	for _, tt := range []struct {
+		op       string
+		actual   interface{}
+		expected interface{}
+	}{
+		{"approximately", 1, 1},
+		{"gt", "abc", 1},
+		{"between", 5, float64(3)},
+		{"one_of", 5, "5"},
+		{"regex", "x", "("},
+		{"length", true, 1},
+		{"type_is", "x", "text"},
+	} {
+		ok, err := evaluateOperator(tt.op, tt.actual, tt.expected)
+		assert.Error(t, err, tt.op)
+		assert.False(t, ok, tt.op)
+	}
+}
+
+// TestCommand_OperatorsAndUnknownTypes tests command assertions through the operator engine
+func TestCommand_OperatorsAndUnknownTypes(t *testing.T) {
+	executor := NewExecutor("http://localhost:8080")
+
+	result := executor.Execute(&TestCase{
+		ID:      "cmd-operators",
+		Type:    "command",
+		Command: &CommandTest{Cmd: "echo", Args: []string{"version 1.4.2"}},
+		Assertions: []Assertion{
+			{Type: "exit_code", Operator: "one_of", Expected: []interface{}{float64(0), float64(2)}},
+			{Type: "stdout", Operator: "regex", Expected: `version \d+\.\d+\.\d+`},
+			{Type: "stdout_contains", Expected: "1.4"},
+		},
+	})
+	assert.Equal(t, "passed", result.Status, "failures: %v", result.Failures)
+
+	result = executor.Execute(&TestCase{
+		ID:      "cmd-unknown",
+		Type:    "command",
+		Command: &CommandTest{Cmd: "echo", Args: []string{"ok"}},
+		Assertions: []Assertion{
+			{Type: "stdout_matches", Expected: "ok"},
+			{Type: "exit_code", Operator: "roughly", Expected: float64(0)},
+		},
+	})
+	assert.Equal(t, "failed", result.Status)
+	assert.Len(t, result.Failures, 2)
+	assert.Contains(t, result.Failures[0], "unsupported assertion type")
+	assert.Contains(t, result.Failures[1], "unsupported operator")
+}
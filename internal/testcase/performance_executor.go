@@ -0,0 +1,361 @@
+package testcase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// latencyBucketsMs are the upper bounds of the latency histogram buckets
+var latencyBucketsMs = []float64{10, 25, 50, 100, 250, 500, 1000, 2500, 5000}
+
+// thresholdPattern matches threshold expressions such as "p95 < 300ms" or "errorRate < 1%"
+var thresholdPattern = regexp.MustCompile(`^\s*([A-Za-z0-9]+)\s*(<=|>=|==|!=|<|>)\s*([0-9]*\.?[0-9]+)\s*(ms|s|%|rps)?\s*$`)
+
+// loadCollector accumulates per-request samples from concurrent workers
+type loadCollector struct {
+	mu          sync.Mutex
+	latencies   []time.Duration
+	statusCodes map[int]int
+	errors      int
+	errorSample map[string]int
+}
+
+func (c *loadCollector) record(latency time.Duration, statusCode int, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.latencies = append(c.latencies, latency)
+	if err != nil {
+		c.errors++
+		if len(c.errorSample) < 10 || c.errorSample[err.Error()] > 0 {
+			c.errorSample[err.Error()]++
+		}
+		return
+	}
+	c.statusCodes[statusCode]++
+	if statusCode >= 400 {
+		c.errors++
+	}
+}
+
+// executePerformance drives the test's HTTP request under load
+func (e *UnifiedTestExecutor) executePerformance(ctx context.Context, tc *TestCase, result *TestResult, hookCtx map[string]interface{}) {
+	if tc.Performance == nil {
+		result.Status = "error"
+		result.Error = "Performance configuration missing"
+		return
+	}
+	if tc.HTTP == nil {
+		result.Status = "error"
+		result.Error = "HTTP configuration missing"
+		return
+	}
+
+	// Render the request once into a copy shared read-only by all virtual users
+	request := templateHTTP(tc.HTTP, hookCtx)
+	if e.variableInjector != nil {
+		if err := e.variableInjector.InjectHTTPVariables(request); err != nil {
+			result.Status = "error"
+			result.Error = fmt.Sprintf("failed to inject variables: %v", err)
+			return
+		}
+	}
+
+	requestBody, err := encodeHTTPBody(request)
+	if err != nil {
+		result.Status = "error"
+		result.Error = err.Error()
+		return
+	}
+	auth, err := e.resolveAuth(request.Auth, hookCtx)
+	if err != nil {
+		result.Status = "error"
+		result.Error = err.Error()
+		return
+	}
+	tlsConfig, err := e.clientTLSConfig()
+	if err != nil {
+		result.Status = "error"
+		result.Error = err.Error()
+		return
+	}
+
+	config := tc.Performance
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	duration := time.Duration(config.Duration) * time.Second
+	if duration <= 0 {
+		duration = 10 * time.Second
+	}
+	rampUp := time.Duration(config.RampUp) * time.Second
+
+	url := e.baseURL + request.Path
+	result.Request = map[string]interface{}{
+		"method":      request.Method,
+		"url":         url,
+		"headers":     request.Headers,
+		"body":        requestBodySummary(request, requestBody),
+		"concurrency": concurrency,
+		"rps":         config.RPS,
+		"duration":    config.Duration,
+		"rampUp":      config.RampUp,
+	}
+
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			MaxIdleConns:        concurrency,
+			MaxIdleConnsPerHost: concurrency,
+			TLSClientConfig:     tlsConfig,
+		},
+	}
+	defer client.CloseIdleConnections()
+
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	// Global rate limiter shared by all workers
+	var tokens <-chan time.Time
+	if config.RPS > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(config.RPS))
+		defer ticker.Stop()
+		tokens = ticker.C
+	}
+
+	collector := &loadCollector{
+		statusCodes: make(map[int]int),
+		errorSample: make(map[string]int),
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		// Ramp-up: spread worker start times evenly across the ramp-up period
+		delay := time.Duration(0)
+		if rampUp > 0 {
+			delay = rampUp * time.Duration(i) / time.Duration(concurrency)
+		}
+
+		wg.Add(1)
+		go func(delay time.Duration) {
+			defer wg.Done()
+
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return
+			}
+
+			for {
+				if tokens != nil {
+					select {
+					case <-tokens:
+					case <-ctx.Done():
+						return
+					}
+				}
+				if ctx.Err() != nil {
+					return
+				}
+
+				latency, statusCode, err := e.loadRequest(ctx, client, request, url, requestBody, auth)
+				// Requests cut off by the end of the run are not counted
+				if ctx.Err() != nil {
+					return
+				}
+				collector.record(latency, statusCode, err)
+			}
+		}(delay)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	metrics := buildLoadMetrics(collector, elapsed)
+	result.Metrics = metrics
+	result.Response = map[string]interface{}{
+		"statusCodes": collector.statusCodes,
+		"errors":      collector.errorSample,
+	}
+
+	if collector.latencies == nil {
+		result.Status = "error"
+		result.Error = "no requests completed during the run"
+		return
+	}
+
+	// Run assertions
+	e.runPerformanceAssertions(tc.Assertions, metrics, result)
+}
+
+// loadRequest issues a single request and measures its latency
+func (e *UnifiedTestExecutor) loadRequest(ctx context.Context, client *http.Client, config *HTTPTest, url string, body *httpRequestBody, auth *HTTPAuth) (time.Duration, int, error) {
+	req, err := http.NewRequestWithContext(ctx, config.Method, url, body.reader())
+	if err != nil {
+		return 0, 0, err
+	}
+	setRequestHeaders(req, config.Headers, body)
+	if err := e.applyAuth(req, auth, body); err != nil {
+		return 0, 0, err
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return time.Since(start), 0, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	return time.Since(start), resp.StatusCode, nil
+}
+
+// buildLoadMetrics summarizes collected samples into latency percentiles, throughput and error rate
+func buildLoadMetrics(c *loadCollector, elapsed time.Duration) map[string]interface{} {
+	total := len(c.latencies)
+	sorted := make([]float64, total)
+	var sum float64
+	for i, l := range c.latencies {
+		ms := float64(l) / float64(time.Millisecond)
+		sorted[i] = ms
+		sum += ms
+	}
+	sort.Float64s(sorted)
+
+	percentile := func(p float64) float64 {
+		if total == 0 {
+			return 0
+		}
+		idx := int(math.Ceil(p/100*float64(total))) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		return round2(sorted[idx])
+	}
+
+	// Histogram with one bucket per upper bound plus an overflow bucket
+	buckets := make([]map[string]interface{}, 0, len(latencyBucketsMs)+1)
+	counts := make([]int, len(latencyBucketsMs)+1)
+	for _, ms := range sorted {
+		i := sort.SearchFloat64s(latencyBucketsMs, ms)
+		counts[i]++
+	}
+	for i, bound := range latencyBucketsMs {
+		buckets = append(buckets, map[string]interface{}{"le": bound, "count": counts[i]})
+	}
+	buckets = append(buckets, map[string]interface{}{"le": "+Inf", "count": counts[len(latencyBucketsMs)]})
+
+	metrics := map[string]interface{}{
+		"totalRequests": total,
+		"failed":        c.errors,
+		"successful":    total - c.errors,
+		"errorRate":     0.0,
+		"throughput":    0.0,
+		"duration":      elapsed.Milliseconds(),
+		"latency": map[string]interface{}{
+			"min":  0.0,
+			"max":  0.0,
+			"mean": 0.0,
+			"p50":  percentile(50),
+			"p90":  percentile(90),
+			"p95":  percentile(95),
+			"p99":  percentile(99),
+		},
+		"histogram": buckets,
+	}
+
+	if total > 0 {
+		metrics["errorRate"] = round2(float64(c.errors) / float64(total) * 100)
+		latency := metrics["latency"].(map[string]interface{})
+		latency["min"] = round2(sorted[0])
+		latency["max"] = round2(sorted[total-1])
+		latency["mean"] = round2(sum / float64(total))
+	}
+	if elapsed > 0 {
+		metrics["throughput"] = round2(float64(total) / elapsed.Seconds())
+	}
+
+	return metrics
+}
+
+// runPerformanceAssertions evaluates threshold assertions against the collected metrics
+func (e *UnifiedTestExecutor) runPerformanceAssertions(assertions []Assertion, metrics map[string]interface{}, result *TestResult) {
+	for _, assertion := range assertions {
+		if assertion.Type != "threshold" {
+			recordAssertion(result, assertion, nil,
+				fmt.Sprintf("unsupported assertion type for performance test: %s", assertion.Type))
+			continue
+		}
+
+		expr := fmt.Sprint(assertion.Expected)
+		ok, actual, err := evaluateThreshold(expr, metrics)
+		switch {
+		case err != nil:
+			recordAssertion(result, assertion, nil, fmt.Sprintf("threshold %q: %v", expr, err))
+		case !ok:
+			recordAssertion(result, assertion, actual, fmt.Sprintf("threshold %q not met: actual %v", expr, actual))
+		default:
+			recordAssertion(result, assertion, actual)
+		}
+	}
+}
+
+// evaluateThreshold checks an expression such as "p95 < 300ms" and returns the actual metric value
+func evaluateThreshold(expr string, metrics map[string]interface{}) (bool, float64, error) {
+	m := thresholdPattern.FindStringSubmatch(expr)
+	if m == nil {
+		return false, 0, fmt.Errorf("invalid threshold expression")
+	}
+	name, op, unit := m[1], m[2], m[4]
+	limit, _ := strconv.ParseFloat(m[3], 64)
+	if unit == "s" {
+		limit *= 1000
+	}
+
+	latency := metrics["latency"].(map[string]interface{})
+	var actual float64
+	switch strings.ToLower(name) {
+	case "p50", "p90", "p95", "p99", "min", "max", "mean":
+		actual, _ = toFloat(latency[strings.ToLower(name)])
+	case "avg":
+		actual, _ = toFloat(latency["mean"])
+	case "errorrate":
+		actual, _ = toFloat(metrics["errorRate"])
+	case "throughput", "rps":
+		actual, _ = toFloat(metrics["throughput"])
+	case "requests", "totalrequests":
+		actual, _ = toFloat(metrics["totalRequests"])
+	default:
+		return false, 0, fmt.Errorf("unknown metric %s", name)
+	}
+
+	switch op {
+	case "<":
+		return actual < limit, actual, nil
+	case "<=":
+		return actual <= limit, actual, nil
+	case ">":
+		return actual > limit, actual, nil
+	case ">=":
+		return actual >= limit, actual, nil
+	case "==":
+		return actual == limit, actual, nil
+	default:
+		return actual != limit, actual, nil
+	}
+}
+
+// round2 rounds to two decimal places
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
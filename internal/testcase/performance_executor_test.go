@@ -0,0 +1,178 @@
+package testcase
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPerformance_MetricsAndThresholds tests a rate-limited run and threshold assertions
+func TestPerformance_MetricsAndThresholds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	result := executor.Execute(&TestCase{
+		ID:          "perf-ok",
+		Type:        "performance",
+		HTTP:        &HTTPTest{Method: "GET", Path: "/health"},
+		Performance: &PerformanceTest{Concurrency: 4, RPS: 50, Duration: 1},
+		Assertions: []Assertion{
+			{Type: "threshold", Expected: "p95 < 500ms"},
+			{Type: "threshold", Expected: "errorRate < 1%"},
+			{Type: "threshold", Expected: "requests >= 10"},
+		},
+	})
+
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+	require.NotNil(t, result.Metrics)
+
+	total := result.Metrics["totalRequests"].(int)
+	assert.InDelta(t, 50, total, 15, "RPS limit should cap the request count")
+	assert.Equal(t, 0.0, result.Metrics["errorRate"])
+	assert.Len(t, result.Metrics["histogram"], len(latencyBucketsMs)+1)
+}
+
+// TestPerformance_ErrorRateThreshold tests that server errors count against the error rate
+func TestPerformance_ErrorRateThreshold(t *testing.T) {
+	var count int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&count, 1)%2 == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	result := executor.Execute(&TestCase{
+		ID:          "perf-errors",
+		Type:        "performance",
+		HTTP:        &HTTPTest{Method: "GET", Path: "/flaky"},
+		Performance: &PerformanceTest{Concurrency: 2, RPS: 40, Duration: 1, RampUp: 1},
+		Assertions: []Assertion{
+			{Type: "threshold", Expected: "errorRate < 1%"},
+			{Type: "threshold", Expected: "p99 < 2s"},
+		},
+	})
+
+	assert.Equal(t, "failed", result.Status)
+	assert.Len(t, result.Failures, 1)
+	assert.Contains(t, result.Failures[0], "errorRate")
+}
+
+// pathInjector fills {{TENANT}} in the path and headers of a request in place, like the
+// environment variable injector
+type pathInjector struct{ fakeEnvironmentInjector }
+
+func (p *pathInjector) InjectHTTPVariables(config *HTTPTest) error {
+	config.Path = strings.ReplaceAll(config.Path, "{{TENANT}}", "acme")
+	headers := make(map[string]string, len(config.Headers))
+	for name, value := range config.Headers {
+		headers[name] = strings.ReplaceAll(value, "{{TENANT}}", "acme")
+	}
+	config.Headers = headers
+	return nil
+}
+
+// TestPerformance_RendersIntoCopy tests that virtual users send the rendered request while the
+// test case keeps its templates
+func TestPerformance_RendersIntoCopy(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[r.URL.Path+" "+r.Header.Get("X-Tenant")]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	executor := NewExecutorWithInjector(server.URL, nil, nil, nil, &pathInjector{})
+	tc := &TestCase{
+		ID:          "perf-template",
+		Type:        "performance",
+		HTTP:        &HTTPTest{Method: "GET", Path: "/{{TENANT}}/health", Headers: map[string]string{"X-Tenant": "{{TENANT}}"}},
+		Performance: &PerformanceTest{Concurrency: 4, RPS: 40, Duration: 1},
+	}
+	result := executor.Execute(tc)
+
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+	assert.Equal(t, "/{{TENANT}}/health", tc.HTTP.Path)
+	assert.Equal(t, "{{TENANT}}", tc.HTTP.Headers["X-Tenant"])
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, seen, 1)
+	assert.Greater(t, seen["/acme/health acme"], 0)
+}
+
+// TestPerformance_HookVariableAuth tests that auth credentials can reference values saved by setup hooks
+func TestPerformance_HookVariableAuth(t *testing.T) {
+	var unauthorized int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"token": "perf-token"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer perf-token" {
+			atomic.AddInt64(&unauthorized, 1)
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	result := executor.Execute(&TestCase{
+		ID:          "perf-auth",
+		Type:        "performance",
+		HTTP:        &HTTPTest{Method: "GET", Path: "/orders", Auth: &HTTPAuth{Type: "bearer", Token: "{{token}}"}},
+		Performance: &PerformanceTest{Concurrency: 2, RPS: 20, Duration: 1},
+		SetupHooks: []Hook{
+			{Type: "http", Name: "login", HTTP: &HTTPTest{Method: "POST", Path: "/login"}, Extract: map[string]string{"token": "$.body.token"}},
+		},
+		Assertions: []Assertion{{Type: "threshold", Expected: "errorRate < 1%"}},
+	})
+
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+	assert.Zero(t, atomic.LoadInt64(&unauthorized))
+}
+
+// TestEvaluateThreshold tests parsing of threshold expressions
+func TestEvaluateThreshold(t *testing.T) {
+	metrics := map[string]interface{}{
+		"errorRate":     0.5,
+		"throughput":    120.0,
+		"totalRequests": 600,
+		"latency":       map[string]interface{}{"p95": 250.0, "mean": 80.0},
+	}
+
+	ok, _, err := evaluateThreshold("p95 < 300ms", metrics)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, _, err = evaluateThreshold("p95 < 0.2s", metrics)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, _, err = evaluateThreshold("throughput >= 100rps", metrics)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	_, _, err = evaluateThreshold("p95 is fast", metrics)
+	assert.Error(t, err)
+
+	_, _, err = evaluateThreshold("p42 < 10ms", metrics)
+	assert.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package testcase
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// cookieAttributes are the Set-Cookie attributes addressable as "name.attribute" in cookie assertions
+var cookieAttributes = map[string]func(c *http.Cookie) interface{}{
+	"value":    func(c *http.Cookie) interface{} { return c.Value },
+	"path":     func(c *http.Cookie) interface{} { return c.Path },
+	"domain":   func(c *http.Cookie) interface{} { return c.Domain },
+	"expires":  func(c *http.Cookie) interface{} { return c.RawExpires },
+	"maxage":   func(c *http.Cookie) interface{} { return c.MaxAge },
+	"secure":   func(c *http.Cookie) interface{} { return c.Secure },
+	"httponly": func(c *http.Cookie) interface{} { return c.HttpOnly },
+	"samesite": func(c *http.Cookie) interface{} { return sameSiteName(c.SameSite) },
+}
+
+// checkHeader checks a response header; Path is the header name and multiple values are joined with ", "
+func checkHeader(assertion Assertion, header http.Header) (interface{}, string) {
+	values := header.Values(assertion.Path)
+	if len(values) == 0 {
+		return nil, checkOperator(assertion, "header "+assertion.Path, nil, false, "equals")
+	}
+	value := strings.Join(values, ", ")
+	return value, checkOperator(assertion, "header "+assertion.Path, value, true, "equals")
+}
+
+// checkCookie checks a cookie set by the response or held by the session.
+// Path is the cookie name, optionally suffixed with an attribute such as "session.httpOnly";
+// attributes are only known for cookies set by the response.
+func checkCookie(assertion Assertion, cookies []*http.Cookie) (interface{}, string) {
+	name, attribute := assertion.Path, "value"
+	if i := strings.LastIndex(name, "."); i > 0 {
+		if _, ok := cookieAttributes[strings.ToLower(name[i+1:])]; ok {
+			name, attribute = name[:i], strings.ToLower(name[i+1:])
+		}
+	}
+
+	subject := fmt.Sprintf("cookie %s", assertion.Path)
+	for _, cookie := range cookies {
+		// Session cookies from the jar carry no Set-Cookie line or attributes
+		if cookie.Name == name && (attribute == "value" || cookie.Raw != "") {
+			value := cookieAttributes[attribute](cookie)
+			return value, checkOperator(assertion, subject, value, true, "equals")
+		}
+	}
+	return nil, checkOperator(assertion, subject, nil, false, "equals")
+}
+
+// checkResponseTime checks the request latency in milliseconds; expected may also be a duration string like "500ms"
+func checkResponseTime(assertion Assertion, elapsed time.Duration) (interface{}, string) {
+	if s, ok := assertion.Expected.(string); ok {
+		if d, err := time.ParseDuration(s); err == nil {
+			assertion.Expected = float64(d) / float64(time.Millisecond)
+		}
+	}
+	ms := elapsedMillis(elapsed)
+	return ms, checkOperator(assertion, "response time (ms)", ms, true, "lte")
+}
+
+// elapsedMillis converts a duration to fractional milliseconds
+func elapsedMillis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// sameSiteName returns the SameSite attribute as written in Set-Cookie, or "" when absent
+func sameSiteName(mode http.SameSite) string {
+	switch mode {
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteNoneMode:
+		return "None"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,80 @@
+package testcase
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newResponseTestServer(delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		http.SetCookie(w, &http.Cookie{
+			Name:     "session",
+			Value:    "abc123",
+			Path:     "/",
+			HttpOnly: true,
+			Secure:   true,
+			SameSite: http.SameSiteStrictMode,
+		})
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("X-Request-Id", "req-1")
+		w.Write([]byte(`{"ok": true}`))
+	}))
+}
+
+// TestResponseAssertions_HeaderCookieAndLatency tests header, cookie and response_time assertions
+func TestResponseAssertions_HeaderCookieAndLatency(t *testing.T) {
+	server := newResponseTestServer(0)
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	result := executor.Execute(&TestCase{
+		ID:   "response-ok",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "GET", Path: "/login"},
+		Assertions: []Assertion{
+			{Type: "header", Path: "content-type", Operator: "regex", Expected: `^application/json`},
+			{Type: "header", Path: "X-Request-Id", Operator: "exists"},
+			{Type: "header", Path: "X-Powered-By", Operator: "not_exists"},
+			{Type: "cookie", Path: "session", Expected: "abc123"},
+			{Type: "cookie", Path: "session.httpOnly", Expected: true},
+			{Type: "cookie", Path: "session.sameSite", Expected: "Strict"},
+			{Type: "response_time", Expected: 2000},
+			{Type: "response_time", Operator: "lt", Expected: "2s"},
+		},
+	})
+
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+	require.NotNil(t, result.Response)
+	assert.Contains(t, result.Response, "headers")
+	assert.Contains(t, result.Response, "responseTimeMs")
+}
+
+// TestResponseAssertions_Failures tests failure messages for missing cookies, header mismatches and slow responses
+func TestResponseAssertions_Failures(t *testing.T) {
+	server := newResponseTestServer(30 * time.Millisecond)
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	result := executor.Execute(&TestCase{
+		ID:   "response-failures",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "GET", Path: "/login"},
+		Assertions: []Assertion{
+			{Type: "header", Path: "X-Request-Id", Expected: "req-2"},
+			{Type: "cookie", Path: "csrf", Operator: "exists"},
+			{Type: "response_time", Expected: "10ms"},
+		},
+	})
+
+	assert.Equal(t, "failed", result.Status)
+	require.Len(t, result.Failures, 3, "failures: %v", result.Failures)
+	assert.Equal(t, "header X-Request-Id: expected req-2, got req-1", result.Failures[0])
+	assert.Equal(t, "cookie csrf should exist", result.Failures[1])
+	assert.Contains(t, result.Failures[2], "response time (ms): expected lte 10")
+}
@@ -0,0 +1,462 @@
+package testcase
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// securityChecks lists the supported security checks in execution order
+var securityChecks = []string{"tls", "headers", "cors", "auth"}
+
+// corsProbeOrigin is an origin no real deployment should trust
+const corsProbeOrigin = "https://cors-probe.invalid"
+
+// securityScan collects findings for a single security test
+type securityScan struct {
+	ctx      context.Context
+	target   *url.URL
+	config   *SecurityTest
+	client   *http.Client
+	timeout  time.Duration
+	ignore   map[string]bool
+	findings []interface{}
+	result   *TestResult
+}
+
+// report records a finding unless it is ignored
+func (s *securityScan) report(id, severity, target, message string) {
+	if s.ignore[id] {
+		return
+	}
+	s.findings = append(s.findings, map[string]interface{}{
+		"id":       id,
+		"check":    strings.SplitN(id, ".", 2)[0],
+		"severity": severity,
+		"target":   target,
+		"message":  message,
+	})
+	s.result.Status = "failed"
+	s.result.Failures = append(s.result.Failures, fmt.Sprintf("[%s] %s: %s (%s)", severity, id, message, target))
+}
+
+// executeSecurity probes a target for TLS, header, CORS and authentication issues
+func (e *UnifiedTestExecutor) executeSecurity(ctx context.Context, tc *TestCase, result *TestResult) {
+	if tc.Security == nil {
+		result.Status = "error"
+		result.Error = "Security configuration missing"
+		return
+	}
+	config := tc.Security
+
+	// Inject environment variables through the HTTP injector
+	probe := &HTTPTest{Method: "GET", Path: config.URL, Headers: config.Headers}
+	if e.variableInjector != nil {
+		if err := e.variableInjector.InjectHTTPVariables(probe); err != nil {
+			result.Status = "error"
+			result.Error = fmt.Sprintf("failed to inject variables: %v", err)
+			return
+		}
+	}
+
+	rawURL := probe.Path
+	if !strings.HasPrefix(rawURL, "http://") && !strings.HasPrefix(rawURL, "https://") {
+		rawURL = e.baseURL + rawURL
+	}
+	target, err := url.Parse(rawURL)
+	if err != nil || target.Host == "" {
+		result.Status = "error"
+		result.Error = fmt.Sprintf("invalid target URL: %s", rawURL)
+		return
+	}
+
+	checks := config.Checks
+	if len(checks) == 0 {
+		checks = securityChecks
+	}
+	for _, check := range checks {
+		if !containsString(securityChecks, check) {
+			result.Status = "error"
+			result.Error = fmt.Sprintf("unsupported security check: %s", check)
+			return
+		}
+	}
+
+	timeout := 10 * time.Second
+	if config.Timeout > 0 {
+		timeout = time.Duration(config.Timeout) * time.Second
+	}
+
+	scan := &securityScan{
+		ctx:     ctx,
+		target:  target,
+		config:  config,
+		timeout: timeout,
+		ignore:  make(map[string]bool),
+		result:  result,
+		// Certificate trust is judged by the tls check, so other probes accept any certificate
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		findings: []interface{}{},
+	}
+	defer scan.client.CloseIdleConnections()
+	for _, id := range config.Ignore {
+		scan.ignore[id] = true
+	}
+
+	result.Request = map[string]interface{}{
+		"url":            target.String(),
+		"checks":         checks,
+		"protectedPaths": config.ProtectedPaths,
+	}
+	response := map[string]interface{}{}
+
+	for _, check := range checks {
+		var err error
+		switch check {
+		case "tls":
+			var info map[string]interface{}
+			info, err = scan.checkTLS()
+			if info != nil {
+				response["tls"] = info
+			}
+		case "headers":
+			err = scan.checkHeaders()
+		case "cors":
+			err = scan.checkCORS()
+		case "auth":
+			err = scan.checkAuth()
+		}
+		if err != nil {
+			result.Status = "error"
+			result.Error = fmt.Sprintf("%s check failed: %v", check, err)
+			break
+		}
+	}
+
+	response["findings"] = scan.findings
+	response["findingCount"] = len(scan.findings)
+	result.Response = response
+
+	// Findings are the checks; use ignore to suppress them
+	for _, assertion := range tc.Assertions {
+		result.Status = "failed"
+		result.Failures = append(result.Failures,
+			fmt.Sprintf("unsupported assertion type for security test: %s", assertion.Type))
+	}
+}
+
+// checkTLS inspects the negotiated protocol, cipher suite and certificate, and probes for legacy protocols and weak ciphers
+func (s *securityScan) checkTLS() (map[string]interface{}, error) {
+	target := s.target.String()
+	if s.target.Scheme != "https" {
+		s.report("tls.plaintext", "high", target, "target is served over plain HTTP")
+		return nil, nil
+	}
+
+	options := s.config.TLS
+	if options == nil {
+		options = &SecurityTLSCheck{}
+	}
+	minVersion, err := parseTLSVersion(options.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+	minCertDays := options.MinCertDays
+	if minCertDays <= 0 {
+		minCertDays = 30
+	}
+
+	state, err := s.handshake(&tls.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	info := map[string]interface{}{
+		"version": tls.VersionName(state.Version),
+		"cipher":  tls.CipherSuiteName(state.CipherSuite),
+	}
+
+	if state.Version < minVersion {
+		s.report("tls.version", "high", target,
+			fmt.Sprintf("negotiated %s, minimum is %s", tls.VersionName(state.Version), tls.VersionName(minVersion)))
+	}
+
+	// Legacy protocol probe: try each version below the minimum on its own
+	for _, version := range []uint16{tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12} {
+		if version >= minVersion {
+			break
+		}
+		if _, err := s.handshake(&tls.Config{MinVersion: version, MaxVersion: version}); err == nil {
+			s.report("tls.legacy_version", "high", target,
+				fmt.Sprintf("server accepts %s", tls.VersionName(version)))
+		}
+	}
+
+	// Weak cipher probe: offer only insecure TLS 1.2 suites
+	weak := make(map[uint16]bool)
+	var weakIDs []uint16
+	for _, suite := range tls.InsecureCipherSuites() {
+		weak[suite.ID] = true
+		weakIDs = append(weakIDs, suite.ID)
+	}
+	if weak[state.CipherSuite] {
+		s.report("tls.weak_cipher", "high", target,
+			fmt.Sprintf("negotiated insecure cipher suite %s", tls.CipherSuiteName(state.CipherSuite)))
+	} else if weakState, err := s.handshake(&tls.Config{MinVersion: tls.VersionTLS10, MaxVersion: tls.VersionTLS12, CipherSuites: weakIDs}); err == nil {
+		s.report("tls.weak_cipher", "high", target,
+			fmt.Sprintf("server accepts insecure cipher suite %s", tls.CipherSuiteName(weakState.CipherSuite)))
+	}
+
+	if len(state.PeerCertificates) == 0 {
+		s.report("tls.certificate_missing", "high", target, "server presented no certificate")
+		return info, nil
+	}
+	leaf := state.PeerCertificates[0]
+	daysRemaining := int(time.Until(leaf.NotAfter).Hours() / 24)
+	info["subject"] = leaf.Subject.String()
+	info["issuer"] = leaf.Issuer.String()
+	info["notAfter"] = leaf.NotAfter.Format(time.RFC3339)
+	info["daysRemaining"] = daysRemaining
+
+	switch {
+	case time.Now().After(leaf.NotAfter):
+		s.report("tls.certificate_expired", "high", target,
+			fmt.Sprintf("certificate expired on %s", leaf.NotAfter.Format("2006-01-02")))
+	case daysRemaining < minCertDays:
+		s.report("tls.certificate_expiring", "medium", target,
+			fmt.Sprintf("certificate expires in %d days (minimum %d)", daysRemaining, minCertDays))
+	}
+
+	if !options.SkipVerify {
+		intermediates := x509.NewCertPool()
+		for _, cert := range state.PeerCertificates[1:] {
+			intermediates.AddCert(cert)
+		}
+		if _, err := leaf.Verify(x509.VerifyOptions{DNSName: s.target.Hostname(), Intermediates: intermediates}); err != nil {
+			s.report("tls.untrusted_certificate", "high", target, err.Error())
+		}
+	}
+
+	return info, nil
+}
+
+// handshake performs a TLS handshake with the target using the given client settings
+func (s *securityScan) handshake(config *tls.Config) (*tls.ConnectionState, error) {
+	address := s.target.Host
+	if s.target.Port() == "" {
+		address = net.JoinHostPort(s.target.Hostname(), "443")
+	}
+	config.ServerName = s.target.Hostname()
+	config.InsecureSkipVerify = true
+
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: s.timeout}, Config: config}
+	conn, err := dialer.DialContext(s.ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	return &state, nil
+}
+
+// checkHeaders verifies that the required security headers are present
+func (s *securityScan) checkHeaders() error {
+	resp, err := s.get(s.target.String(), s.config.Headers)
+	if err != nil {
+		return err
+	}
+
+	required := s.config.RequiredHeaders
+	if len(required) == 0 {
+		required = []string{"Content-Security-Policy", "X-Frame-Options"}
+		if s.target.Scheme == "https" {
+			required = append([]string{"Strict-Transport-Security"}, required...)
+		}
+	}
+
+	target := s.target.String()
+	for _, name := range required {
+		id := "headers." + securityHeaderID(name)
+		value := resp.Header.Get(name)
+
+		switch strings.ToLower(name) {
+		case "x-frame-options":
+			// CSP frame-ancestors supersedes X-Frame-Options
+			if value == "" && strings.Contains(resp.Header.Get("Content-Security-Policy"), "frame-ancestors") {
+				continue
+			}
+		case "strict-transport-security":
+			if value != "" && hstsMaxAge(value) <= 0 {
+				s.report(id, "medium", target, fmt.Sprintf("%s has no positive max-age: %q", name, value))
+				continue
+			}
+		}
+
+		if value == "" {
+			s.report(id, "medium", target, fmt.Sprintf("missing %s header", name))
+		}
+	}
+	return nil
+}
+
+// checkCORS probes the target with untrusted origins and inspects the CORS response headers
+func (s *securityScan) checkCORS() error {
+	target := s.target.String()
+
+	for _, origin := range []string{corsProbeOrigin, "null"} {
+		headers := map[string]string{"Origin": origin}
+		for k, v := range s.config.Headers {
+			headers[k] = v
+		}
+		resp, err := s.get(target, headers)
+		if err != nil {
+			return err
+		}
+
+		allowOrigin := resp.Header.Get("Access-Control-Allow-Origin")
+		credentials := strings.EqualFold(resp.Header.Get("Access-Control-Allow-Credentials"), "true")
+
+		switch {
+		case allowOrigin == "*" && credentials:
+			s.report("cors.wildcard_credentials", "high", target,
+				"Access-Control-Allow-Origin is * while credentials are allowed")
+		case allowOrigin == "*" && len(s.config.AllowedOrigins) > 0 && origin == corsProbeOrigin:
+			s.report("cors.wildcard_origin", "medium", target,
+				"Access-Control-Allow-Origin is * but only specific origins should be allowed")
+		case allowOrigin == origin && origin == "null":
+			s.report("cors.null_origin", severityFor(credentials), target,
+				"the null origin is allowed")
+		case allowOrigin == origin && !containsString(s.config.AllowedOrigins, origin):
+			s.report("cors.reflected_origin", severityFor(credentials), target,
+				fmt.Sprintf("untrusted origin %s is reflected in Access-Control-Allow-Origin", origin))
+		}
+	}
+	return nil
+}
+
+// checkAuth requests each protected path without credentials and expects it to be rejected
+func (s *securityScan) checkAuth() error {
+	for _, entry := range s.config.ProtectedPaths {
+		method, path := "GET", strings.TrimSpace(entry)
+		if m, p, found := strings.Cut(path, " "); found {
+			method, path = strings.ToUpper(m), strings.TrimSpace(p)
+		}
+
+		ref, err := url.Parse(path)
+		if err != nil {
+			return fmt.Errorf("invalid protected path %q: %w", entry, err)
+		}
+		endpoint := s.target.ResolveReference(ref).String()
+
+		req, err := http.NewRequestWithContext(s.ctx, method, endpoint, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return err
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			s.report("auth.unauthenticated_access", "high", endpoint,
+				fmt.Sprintf("%s without credentials returned %d", method, resp.StatusCode))
+		}
+	}
+	return nil
+}
+
+// get issues a GET request and discards the body
+func (s *securityScan) get(target string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(s.ctx, "GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp, nil
+}
+
+// parseTLSVersion parses versions such as "1.2", "TLS1.2" or "TLSv1.3"; empty means TLS 1.2
+func parseTLSVersion(version string) (uint16, error) {
+	v := strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(version)), "tls"), "v")
+	switch strings.TrimSpace(v) {
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version: %s", version)
+	}
+}
+
+// securityHeaderID returns the finding ID suffix for a header name
+func securityHeaderID(name string) string {
+	switch strings.ToLower(name) {
+	case "strict-transport-security":
+		return "hsts"
+	case "content-security-policy":
+		return "csp"
+	default:
+		return strings.ReplaceAll(strings.ToLower(name), "-", "_")
+	}
+}
+
+// hstsMaxAge extracts the max-age directive from a Strict-Transport-Security value
+func hstsMaxAge(value string) int {
+	for _, directive := range strings.Split(value, ";") {
+		name, v, found := strings.Cut(strings.TrimSpace(directive), "=")
+		if found && strings.EqualFold(name, "max-age") {
+			n, err := strconv.Atoi(strings.Trim(v, `"`))
+			if err == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// severityFor rates CORS findings higher when credentials are exposed
+func severityFor(credentials bool) string {
+	if credentials {
+		return "high"
+	}
+	return "medium"
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
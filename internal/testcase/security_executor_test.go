@@ -0,0 +1,128 @@
+package testcase
+
+import (
+	"crypto/tls"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startSecurityServer starts a TLS server; hardened controls whether it follows security best practices
+func startSecurityServer(t *testing.T, hardened bool) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hardened {
+			w.Header().Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+			w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+			if r.Header.Get("Authorization") == "" && r.URL.Path == "/admin" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		} else if origin := r.Header.Get("Origin"); origin != "" {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	// Rejected probe handshakes are expected; keep them out of the test log
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	if !hardened {
+		server.TLS = &tls.Config{MinVersion: tls.VersionTLS10}
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// findingIDs extracts the finding IDs from a security test response
+func findingIDs(t *testing.T, result *TestResult) []string {
+	findings, ok := result.Response["findings"].([]interface{})
+	require.True(t, ok, "response has no findings: %v", result.Response)
+
+	ids := make([]string, 0, len(findings))
+	for _, f := range findings {
+		ids = append(ids, f.(map[string]interface{})["id"].(string))
+	}
+	return ids
+}
+
+// TestSecurity_HardenedTarget tests that a well-configured target produces no findings
+func TestSecurity_HardenedTarget(t *testing.T) {
+	server := startSecurityServer(t, true)
+	executor := NewExecutor(server.URL)
+
+	result := executor.Execute(&TestCase{
+		ID:   "sec-hardened",
+		Type: "security",
+		Security: &SecurityTest{
+			URL:            "/",
+			TLS:            &SecurityTLSCheck{SkipVerify: true},
+			ProtectedPaths: []string{"/admin"},
+		},
+	})
+
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+	assert.Empty(t, findingIDs(t, result))
+	assert.Equal(t, "TLS 1.3", result.Response["tls"].(map[string]interface{})["version"])
+}
+
+// TestSecurity_WeakTarget tests that each misconfiguration is reported as a finding
+func TestSecurity_WeakTarget(t *testing.T) {
+	server := startSecurityServer(t, false)
+	executor := NewExecutor(server.URL)
+
+	result := executor.Execute(&TestCase{
+		ID:   "sec-weak",
+		Type: "security",
+		Security: &SecurityTest{
+			TLS:            &SecurityTLSCheck{MinCertDays: 1000000},
+			ProtectedPaths: []string{"DELETE /admin/users/1"},
+			Ignore:         []string{"headers.csp"},
+		},
+	})
+
+	assert.Equal(t, "failed", result.Status)
+	ids := findingIDs(t, result)
+	for _, id := range []string{
+		"tls.legacy_version",
+		"tls.certificate_expiring",
+		"tls.untrusted_certificate",
+		"headers.hsts",
+		"headers.x_frame_options",
+		"cors.reflected_origin",
+		"cors.null_origin",
+		"auth.unauthenticated_access",
+	} {
+		assert.Contains(t, ids, id)
+	}
+	assert.NotContains(t, ids, "headers.csp")
+	assert.Len(t, result.Failures, len(ids))
+}
+
+// TestSecurity_PlainHTTP tests the TLS finding for plain HTTP targets and rejection of unknown checks
+func TestSecurity_PlainHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	executor := NewExecutor(server.URL)
+
+	result := executor.Execute(&TestCase{
+		ID:       "sec-plain",
+		Type:     "security",
+		Security: &SecurityTest{Checks: []string{"tls"}},
+	})
+	assert.Equal(t, "failed", result.Status)
+	assert.Equal(t, []string{"tls.plaintext"}, findingIDs(t, result))
+
+	result = executor.Execute(&TestCase{
+		ID:       "sec-unknown",
+		Type:     "security",
+		Security: &SecurityTest{Checks: []string{"xss"}},
+	})
+	assert.Equal(t, "error", result.Status)
+	assert.Contains(t, result.Error, "xss")
+}
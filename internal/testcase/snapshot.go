@@ -0,0 +1,323 @@
+package testcase
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"test-management-service/internal/models"
+)
+
+// defaultSnapshotName is used when a snapshot assertion does not name its snapshot
+const defaultSnapshotName = "default"
+
+// snapshotOptions configures a snapshot assertion; it is read from the assertion's expected value
+type snapshotOptions struct {
+	Name      string          `json:"name"`
+	Ignore    []string        `json:"ignore"`    // JSONPaths removed before comparing
+	Normalize []normalizeRule `json:"normalize"` // replacements for volatile values
+}
+
+// normalizeRule replaces volatile values before comparing.
+// With only Path, matched values are replaced entirely; with Regex, matching parts of
+// string values (under Path, or anywhere when Path is empty) are replaced.
+type normalizeRule struct {
+	Path    string `json:"path,omitempty"`
+	Regex   string `json:"regex,omitempty"`
+	Replace string `json:"replace"`
+}
+
+// parseSnapshotOptions reads snapshot options from an assertion's expected value, which may be
+// omitted, a snapshot name, or an options object
+func parseSnapshotOptions(expected interface{}) (*snapshotOptions, error) {
+	opts := &snapshotOptions{}
+	switch v := expected.(type) {
+	case nil:
+	case string:
+		opts.Name = v
+	case map[string]interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, opts); err != nil {
+			return nil, fmt.Errorf("invalid snapshot options: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("expected must be a snapshot name or an options object, got %T", expected)
+	}
+
+	if opts.Name == "" {
+		opts.Name = defaultSnapshotName
+	}
+	for _, rule := range opts.Normalize {
+		if rule.Path == "" && rule.Regex == "" {
+			return nil, fmt.Errorf("normalize rule needs a path or a regex")
+		}
+	}
+	return opts, nil
+}
+
+// applySnapshotRules returns a copy of value with ignored paths removed and normalize rules applied
+func applySnapshotRules(value interface{}, opts *snapshotOptions) (interface{}, error) {
+	out := normalizeJSON(value)
+
+	for _, path := range opts.Ignore {
+		expr, err := parseJSONPath(path)
+		if err != nil {
+			return nil, err
+		}
+		if out, err = expr.Remove(out); err != nil {
+			return nil, fmt.Errorf("ignore %s: %w", path, err)
+		}
+	}
+
+	for _, rule := range opts.Normalize {
+		var re *regexp.Regexp
+		if rule.Regex != "" {
+			var err error
+			if re, err = regexp.Compile(rule.Regex); err != nil {
+				return nil, fmt.Errorf("invalid normalize regex %q: %w", rule.Regex, err)
+			}
+		}
+
+		if rule.Path == "" {
+			out = replaceStrings(out, re, rule.Replace)
+			continue
+		}
+
+		expr, err := parseJSONPath(rule.Path)
+		if err != nil {
+			return nil, err
+		}
+		out, err = expr.Modify(out, func(element interface{}) (interface{}, bool) {
+			if re == nil {
+				return rule.Replace, true
+			}
+			return replaceStrings(element, re, rule.Replace), true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("normalize %s: %w", rule.Path, err)
+		}
+	}
+
+	return out, nil
+}
+
+// replaceStrings replaces regex matches in every string value of a JSON tree
+func replaceStrings(value interface{}, re *regexp.Regexp, replace string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return re.ReplaceAllString(v, replace)
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = replaceStrings(item, re, replace)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = replaceStrings(item, re, replace)
+		}
+	}
+	return value
+}
+
+// diffJSON returns the differences between a baseline and an actual value as
+// {path, op, expected, actual} entries, where op is added, removed or changed
+func diffJSON(path string, expected, actual interface{}) []interface{} {
+	var diff []interface{}
+
+	switch exp := expected.(type) {
+	case map[string]interface{}:
+		act, ok := actual.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(exp)+len(act))
+		for key := range exp {
+			keys = append(keys, key)
+		}
+		for key := range act {
+			if _, ok := exp[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			expItem, inExpected := exp[key]
+			actItem, inActual := act[key]
+			child := childJSONPath(path, key)
+			switch {
+			case !inActual:
+				diff = append(diff, diffEntry(child, "removed", expItem, nil))
+			case !inExpected:
+				diff = append(diff, diffEntry(child, "added", nil, actItem))
+			default:
+				diff = append(diff, diffJSON(child, expItem, actItem)...)
+			}
+		}
+		return diff
+
+	case []interface{}:
+		act, ok := actual.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(exp) || i < len(act); i++ {
+			child := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(act):
+				diff = append(diff, diffEntry(child, "removed", exp[i], nil))
+			case i >= len(exp):
+				diff = append(diff, diffEntry(child, "added", nil, act[i]))
+			default:
+				diff = append(diff, diffJSON(child, exp[i], act[i])...)
+			}
+		}
+		return diff
+
+	case string:
+		// Multi-line text (e.g. command output) is compared line by line
+		act, ok := actual.(string)
+		if !ok || !strings.Contains(exp, "\n") || !strings.Contains(act, "\n") {
+			break
+		}
+		return diffJSON(path, stringsToValues(strings.Split(exp, "\n")), stringsToValues(strings.Split(act, "\n")))
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		diff = append(diff, diffEntry(path, "changed", expected, actual))
+	}
+	return diff
+}
+
+// diffEntry builds a single diff entry
+func diffEntry(path, op string, expected, actual interface{}) map[string]interface{} {
+	entry := map[string]interface{}{"path": path, "op": op}
+	if op != "added" {
+		entry["expected"] = expected
+	}
+	if op != "removed" {
+		entry["actual"] = actual
+	}
+	return entry
+}
+
+// identifierPattern matches object keys that can use dot notation in a JSONPath
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// childJSONPath appends an object key to a JSONPath
+func childJSONPath(path, key string) string {
+	if identifierPattern.MatchString(key) {
+		return path + "." + key
+	}
+	return fmt.Sprintf("%s['%s']", path, strings.ReplaceAll(key, "'", "\\'"))
+}
+
+// stringsToValues converts a string slice to a generic JSON array
+func stringsToValues(items []string) []interface{} {
+	values := make([]interface{}, len(items))
+	for i, item := range items {
+		values[i] = item
+	}
+	return values
+}
+
+// describeDiff summarizes the first few diff entries for a failure message
+func describeDiff(diff []interface{}) string {
+	const maxEntries = 3
+	parts := make([]string, 0, maxEntries)
+	for i, item := range diff {
+		if i == maxEntries {
+			parts = append(parts, fmt.Sprintf("and %d more", len(diff)-maxEntries))
+			break
+		}
+		entry := item.(map[string]interface{})
+		parts = append(parts, fmt.Sprintf("%s %s", entry["op"], entry["path"]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// checkSnapshot compares a response against the stored snapshot of the test.
+// The first run records the baseline; a mismatch is stored as pending for review and
+// the structured diff is returned as the actual value.
+func (e *UnifiedTestExecutor) checkSnapshot(testID string, assertion Assertion, subject interface{}) (interface{}, string) {
+	if e.snapshotRepo == nil {
+		return nil, "snapshot: snapshot repository not configured"
+	}
+
+	opts, err := parseSnapshotOptions(assertion.Expected)
+	if err != nil {
+		return nil, fmt.Sprintf("snapshot: %v", err)
+	}
+
+	if assertion.Path != "" {
+		value, found, err := LookupJSONPath(subject, assertion.Path)
+		if err != nil {
+			return nil, fmt.Sprintf("snapshot %s: %v", opts.Name, err)
+		}
+		if !found {
+			return nil, fmt.Sprintf("snapshot %s: JSON path %s not found", opts.Name, assertion.Path)
+		}
+		subject = value
+	}
+
+	current, err := applySnapshotRules(subject, opts)
+	if err != nil {
+		return nil, fmt.Sprintf("snapshot %s: %v", opts.Name, err)
+	}
+
+	snapshot, err := e.snapshotRepo.GetSnapshot(testID, opts.Name)
+	if err != nil {
+		return nil, fmt.Sprintf("snapshot %s: failed to load snapshot: %v", opts.Name, err)
+	}
+
+	// First run: record the baseline
+	if snapshot == nil {
+		now := time.Now()
+		snapshot = &models.Snapshot{
+			TestID:     testID,
+			Name:       opts.Name,
+			Status:     "accepted",
+			Baseline:   models.JSONValue{Data: current},
+			AcceptedAt: &now,
+		}
+		if err := e.snapshotRepo.SaveSnapshot(snapshot); err != nil {
+			return nil, fmt.Sprintf("snapshot %s: failed to record baseline: %v", opts.Name, err)
+		}
+		fmt.Printf("[snapshot] Recorded baseline %s for test %s\n", opts.Name, testID)
+		return nil, ""
+	}
+
+	// Rules are re-applied to the baseline so that changing them does not require re-recording
+	baseline, err := applySnapshotRules(snapshot.Baseline.Data, opts)
+	if err != nil {
+		return nil, fmt.Sprintf("snapshot %s: %v", opts.Name, err)
+	}
+
+	diff := diffJSON("$", baseline, current)
+	if len(diff) == 0 {
+		if snapshot.Status != "accepted" {
+			snapshot.Status = "accepted"
+			snapshot.Pending = models.JSONValue{}
+			snapshot.Diff = nil
+			if err := e.snapshotRepo.SaveSnapshot(snapshot); err != nil {
+				fmt.Printf("[snapshot] Failed to clear pending snapshot %s: %v\n", opts.Name, err)
+			}
+		}
+		return nil, ""
+	}
+
+	snapshot.Status = "pending"
+	snapshot.Pending = models.JSONValue{Data: current}
+	snapshot.Diff = models.JSONArray(diff)
+	if err := e.snapshotRepo.SaveSnapshot(snapshot); err != nil {
+		fmt.Printf("[snapshot] Failed to store pending snapshot %s: %v\n", opts.Name, err)
+	}
+
+	return diff, fmt.Sprintf("snapshot %s: %d difference(s) from baseline: %s", opts.Name, len(diff), describeDiff(diff))
+}
@@ -0,0 +1,129 @@
+package testcase
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"test-management-service/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSnapshotRepository keeps snapshots in memory
+type fakeSnapshotRepository map[string]*models.Snapshot
+
+func (r fakeSnapshotRepository) GetSnapshot(testID, name string) (*models.Snapshot, error) {
+	return r[testID+"/"+name], nil
+}
+
+func (r fakeSnapshotRepository) SaveSnapshot(snapshot *models.Snapshot) error {
+	r[snapshot.TestID+"/"+snapshot.Name] = snapshot
+	return nil
+}
+
+// TestSnapshot_RecordCompareAndDiff tests baseline recording, ignore/normalize rules and diffs
+func TestSnapshot_RecordCompareAndDiff(t *testing.T) {
+	var count int64
+	var name atomic.Value
+	name.Store("alice")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt64(&count, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id": %d, "name": %q, "createdAt": %q, "roles": ["admin"], "token": "tok-%d"}`,
+			n, name.Load(), time.Now().Add(time.Duration(n)*time.Second).Format(time.RFC3339), n)
+	}))
+	defer server.Close()
+
+	repo := fakeSnapshotRepository{}
+	executor := NewExecutor(server.URL)
+	executor.SetSnapshotRepository(repo)
+
+	tc := &TestCase{
+		ID:   "snapshot-user",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "GET", Path: "/user"},
+		Assertions: []Assertion{
+			{Type: "snapshot", Expected: map[string]interface{}{
+				"ignore": []interface{}{"$.id"},
+				"normalize": []interface{}{
+					map[string]interface{}{"path": "$.token", "replace": "<token>"},
+					map[string]interface{}{"regex": `\d{4}-\d{2}-\d{2}T[\d:]+(Z|[+-][\d:]+)`, "replace": "<time>"},
+				},
+			}},
+		},
+	}
+
+	// First run records the baseline
+	result := executor.Execute(tc)
+	assert.Equal(t, "passed", result.Status, "failures: %v", result.Failures)
+	snapshot := repo["snapshot-user/default"]
+	require.NotNil(t, snapshot)
+	assert.Equal(t, "accepted", snapshot.Status)
+	assert.Equal(t, map[string]interface{}{
+		"name": "alice", "createdAt": "<time>", "roles": []interface{}{"admin"}, "token": "<token>",
+	}, snapshot.Baseline.Data)
+
+	// Volatile fields differ but are ignored or normalized
+	result = executor.Execute(tc)
+	assert.Equal(t, "passed", result.Status, "failures: %v", result.Failures)
+
+	// A real change fails with a structured diff and leaves a pending snapshot
+	name.Store("bob")
+	result = executor.Execute(tc)
+	assert.Equal(t, "failed", result.Status)
+	require.Len(t, result.Failures, 1)
+	assert.Equal(t, "snapshot default: 1 difference(s) from baseline: changed $.name", result.Failures[0])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"path": "$.name", "op": "changed", "expected": "alice", "actual": "bob"},
+	}, result.Assertions[0].Actual)
+	assert.Equal(t, "pending", snapshot.Status)
+	assert.Equal(t, "bob", snapshot.Pending.Data.(map[string]interface{})["name"])
+}
+
+// TestSnapshot_CommandOutput tests line-by-line diffs of command output
+func TestSnapshot_CommandOutput(t *testing.T) {
+	repo := fakeSnapshotRepository{
+		"snapshot-cmd/listing": {
+			TestID:   "snapshot-cmd",
+			Name:     "listing",
+			Status:   "accepted",
+			Baseline: models.JSONValue{Data: "a\nb\nc\n"},
+		},
+	}
+	executor := NewExecutor("")
+	executor.SetSnapshotRepository(repo)
+
+	result := executor.Execute(&TestCase{
+		ID:         "snapshot-cmd",
+		Type:       "command",
+		Command:    &CommandTest{Cmd: "printf", Args: []string{`a\nx\nc\nd\n`}},
+		Assertions: []Assertion{{Type: "snapshot", Expected: "listing"}},
+	})
+
+	assert.Equal(t, "failed", result.Status)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"path": "$[1]", "op": "changed", "expected": "b", "actual": "x"},
+		map[string]interface{}{"path": "$[3]", "op": "changed", "expected": "", "actual": "d"},
+		map[string]interface{}{"path": "$[4]", "op": "added", "actual": ""},
+	}, result.Assertions[0].Actual)
+}
+
+// TestDiffJSON tests added, removed and type-changed entries
+func TestDiffJSON(t *testing.T) {
+	diff := diffJSON("$",
+		map[string]interface{}{"a": 1.0, "b": []interface{}{1.0, 2.0}, "odd key": "x"},
+		map[string]interface{}{"b": []interface{}{1.0}, "c": true, "odd key": map[string]interface{}{}},
+	)
+
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"path": "$.a", "op": "removed", "expected": 1.0},
+		map[string]interface{}{"path": "$.b[1]", "op": "removed", "expected": 2.0},
+		map[string]interface{}{"path": "$.c", "op": "added", "actual": true},
+		map[string]interface{}{"path": "$['odd key']", "op": "changed", "expected": "x", "actual": map[string]interface{}{}},
+	}, diff)
+}
@@ -0,0 +1,152 @@
+package testcase
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// tlsEnvironmentOverrides maps active environment variables to the TLS settings they override
+var tlsEnvironmentOverrides = map[string]func(config *TLSConfig, value string){
+	"TLS_CA_CERT":     func(config *TLSConfig, value string) { config.CACert = value },
+	"TLS_CLIENT_CERT": func(config *TLSConfig, value string) { config.ClientCert = value },
+	"TLS_CLIENT_KEY":  func(config *TLSConfig, value string) { config.ClientKey = value },
+	"TLS_SERVER_NAME": func(config *TLSConfig, value string) { config.ServerName = value },
+	"TLS_INSECURE_SKIP_VERIFY": func(config *TLSConfig, value string) {
+		config.InsecureSkipVerify, _ = strconv.ParseBool(value)
+	},
+}
+
+// WithTLSConfig returns a copy of the executor whose HTTP and WebSocket connections use the given TLS settings
+func (e *UnifiedTestExecutor) WithTLSConfig(config *TLSConfig) *UnifiedTestExecutor {
+	clone := *e
+	clone.tlsConfig = config
+	return &clone
+}
+
+// resolveTLSConfig merges the executor's TLS settings with overrides from the active environment
+// and resolves {{NAME}} references. It returns nil when no TLS settings apply.
+func (e *UnifiedTestExecutor) resolveTLSConfig() (*TLSConfig, error) {
+	var resolved TLSConfig
+	if e.tlsConfig != nil {
+		resolved = *e.tlsConfig
+	}
+
+	if e.variableInjector != nil {
+		if envVars, err := e.variableInjector.GetActiveEnvironmentVariables(); err == nil {
+			for name, override := range tlsEnvironmentOverrides {
+				if value, ok := envVars[name]; ok && value != "" {
+					override(&resolved, value)
+				}
+			}
+		}
+	}
+
+	if resolved == (TLSConfig{}) {
+		return nil, nil
+	}
+
+	missing := e.resolveReferences(nil, &resolved.CACert, &resolved.ClientCert, &resolved.ClientKey, &resolved.ServerName)
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("tls: variable %s is not set", strings.Join(missing, ", "))
+	}
+	return &resolved, nil
+}
+
+// clientTLSConfig builds the crypto/tls configuration for outgoing connections,
+// or returns nil to use the defaults
+func (e *UnifiedTestExecutor) clientTLSConfig() (*tls.Config, error) {
+	config, err := e.resolveTLSConfig()
+	if err != nil || config == nil {
+		return nil, err
+	}
+	return buildTLSConfig(config)
+}
+
+// httpClient returns the HTTP client for the executor's TLS settings. Clients are cached
+// by their resolved settings so connections are reused across tests.
+func (e *UnifiedTestExecutor) httpClient() (*http.Client, error) {
+	config, err := e.resolveTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return e.client, nil
+	}
+
+	data, _ := json.Marshal(config)
+	sum := sha256.Sum256(data)
+	key := hex.EncodeToString(sum[:])
+	if cached, ok := e.tlsClients.Load(key); ok {
+		return cached.(*http.Client), nil
+	}
+
+	tlsConfig, err := buildTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	client, _ := e.tlsClients.LoadOrStore(key, &http.Client{Transport: executionTransport(transport)})
+	return client.(*http.Client), nil
+}
+
+// buildTLSConfig loads the CA bundle and client key pair of a resolved TLS configuration
+func buildTLSConfig(config *TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         config.ServerName,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+
+	if config.CACert != "" {
+		data, err := pemData(config.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("tls: failed to load caCert: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("tls: caCert contains no certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.ClientCert != "" || config.ClientKey != "" {
+		if config.ClientCert == "" || config.ClientKey == "" {
+			return nil, fmt.Errorf("tls: clientCert and clientKey must be set together")
+		}
+		cert, err := pemData(config.ClientCert)
+		if err != nil {
+			return nil, fmt.Errorf("tls: failed to load clientCert: %w", err)
+		}
+		key, err := pemData(config.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("tls: failed to load clientKey: %w", err)
+		}
+		pair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("tls: invalid client key pair: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+
+	return tlsConfig, nil
+}
+
+// pemData returns PEM content given inline, or reads it from the file at value
+func pemData(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
@@ -0,0 +1,174 @@
+package testcase
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCertificate is a generated certificate with its PEM encoding
+type testCertificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM string
+	keyPEM  string
+}
+
+// issueCertificate generates a certificate signed by parent, or a self-signed CA if parent is nil
+func issueCertificate(t *testing.T, commonName string, dnsNames []string, parent *testCertificate) *testCertificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCertificate{
+		cert:    cert,
+		key:     key,
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+}
+
+// TestTLS_MutualTLS tests client certificates, a custom CA bundle and the SNI override
+func TestTLS_MutualTLS(t *testing.T) {
+	ca := issueCertificate(t, "Test CA", nil, nil)
+	serverCert := issueCertificate(t, "api.internal", []string{"api.internal"}, ca)
+	clientCert := issueCertificate(t, "test-runner", nil, ca)
+
+	serverPair, err := tls.X509KeyPair([]byte(serverCert.certPEM), []byte(serverCert.keyPEM))
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"client":     r.TLS.PeerCertificates[0].Subject.CommonName,
+			"serverName": r.TLS.ServerName,
+		})
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverPair},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	// Client certificates are stored as secret environment variables
+	injector := &fakeEnvironmentInjector{
+		envID: "dev",
+		vars: map[string]map[string]string{
+			"dev": {"CLIENT_CERT": clientCert.certPEM, "CLIENT_KEY": clientCert.keyPEM},
+		},
+	}
+	executor := NewExecutorWithInjector(server.URL, nil, nil, nil, injector)
+	test := &TestCase{
+		ID:   "tls-mtls",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "GET", Path: "/whoami"},
+		Assertions: []Assertion{
+			{Type: "json_path", Path: "$.client", Expected: "test-runner"},
+			{Type: "json_path", Path: "$.serverName", Expected: "api.internal"},
+		},
+	}
+
+	result := executor.Execute(test)
+	assert.Equal(t, "error", result.Status)
+	assert.Contains(t, result.Error, "failed to verify certificate")
+
+	group := &TLSConfig{CACert: ca.certPEM, ClientCert: "{{CLIENT_CERT}}", ClientKey: "{{CLIENT_KEY}}", ServerName: "api.internal"}
+	result = executor.WithTLSConfig(group).Execute(test)
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+
+	// Without the SNI override the certificate does not match the address
+	result = executor.WithTLSConfig(&TLSConfig{CACert: ca.certPEM, ClientCert: "{{CLIENT_CERT}}", ClientKey: "{{CLIENT_KEY}}"}).Execute(test)
+	assert.Equal(t, "error", result.Status)
+	assert.Contains(t, result.Error, "doesn't contain any IP SANs")
+
+	// Without a client certificate the server rejects the handshake
+	result = executor.WithTLSConfig(&TLSConfig{CACert: ca.certPEM, ServerName: "api.internal"}).Execute(test)
+	assert.Equal(t, "error", result.Status)
+
+	result = executor.WithTLSConfig(&TLSConfig{ClientCert: "{{CLIENT_CERT}}"}).Execute(test)
+	assert.Equal(t, "error", result.Status)
+	assert.Equal(t, "tls: clientCert and clientKey must be set together", result.Error)
+}
+
+// TestTLS_EnvironmentOverrides tests that the active environment overrides group TLS settings
+func TestTLS_EnvironmentOverrides(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+
+	injector := &fakeEnvironmentInjector{
+		envID: "dev",
+		vars: map[string]map[string]string{
+			"dev":     {"TLS_INSECURE_SKIP_VERIFY": "true"},
+			"staging": {"TLS_CA_CERT": caFile, "TLS_SERVER_NAME": "example.com"},
+			"broken":  {"TLS_CA_CERT": "{{MISSING_CA}}"},
+		},
+	}
+	executor := NewExecutorWithInjector(server.URL, nil, nil, nil, injector).WithTLSConfig(&TLSConfig{ServerName: "api.internal"})
+	test := &TestCase{
+		ID:         "tls-override",
+		Type:       "http",
+		HTTP:       &HTTPTest{Method: "GET", Path: "/health"},
+		Assertions: []Assertion{{Type: "status_code", Expected: 200}},
+	}
+
+	result := executor.Execute(test)
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+
+	// The CA bundle is read from a file and the server name overrides the group's
+	injector.envID = "staging"
+	result = executor.Execute(test)
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+
+	injector.envID = "broken"
+	result = executor.Execute(test)
+	assert.Equal(t, "error", result.Status)
+	assert.Equal(t, "tls: variable MISSING_CA is not set", result.Error)
+}
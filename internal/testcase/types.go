@@ -5,14 +5,20 @@ import "time"
 
 // TestCase represents a test case to be executed
 type TestCase struct {
-	ID         string       `json:"id"`
-	Name       string       `json:"name"`
-	Type       string       `json:"type"` // http, command, workflow, integration, etc.
-	GroupID    string       `json:"groupId,omitempty"`
-	Priority   string       `json:"priority,omitempty"`
-	HTTP       *HTTPTest    `json:"http,omitempty"`
-	Command    *CommandTest `json:"command,omitempty"`
-	Assertions []Assertion  `json:"assertions,omitempty"`
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Type        string           `json:"type"` // http, command, workflow, grpc, websocket, database, performance, security, etc.
+	GroupID     string           `json:"groupId,omitempty"`
+	Priority    string           `json:"priority,omitempty"`
+	HTTP        *HTTPTest        `json:"http,omitempty"`
+	Command     *CommandTest     `json:"command,omitempty"`
+	GRPC        *GRPCTest        `json:"grpc,omitempty"`
+	WebSocket   *WebSocketTest   `json:"websocket,omitempty"`
+	Database    *DatabaseTest    `json:"database,omitempty"`
+	Performance *PerformanceTest `json:"performance,omitempty"`
+	Security    *SecurityTest    `json:"security,omitempty"`
+	Assertions  []Assertion      `json:"assertions,omitempty"`
+	Timeout     int              `json:"timeout,omitempty"` // seconds, including hooks; defaults to 300
 
 	// Workflow integration support
 	WorkflowID  string      `json:"workflowId,omitempty"`  // Mode 1: Reference workflow ID
@@ -21,53 +27,223 @@ type TestCase struct {
 	// Lifecycle hooks
 	SetupHooks    []Hook `json:"setupHooks,omitempty"`
 	TeardownHooks []Hook `json:"teardownHooks,omitempty"`
+
+	// Values saved from the response for teardown hooks and later tests of a run
+	Extract []Extraction `json:"extract,omitempty"`
 }
 
 // HTTPTest represents an HTTP test configuration
 type HTTPTest struct {
-	Method  string                 `json:"method"`
-	Path    string                 `json:"path"`
-	Headers map[string]string      `json:"headers,omitempty"`
-	Body    map[string]interface{} `json:"body,omitempty"`
+	Method       string                 `json:"method"`
+	Path         string                 `json:"path"`
+	Headers      map[string]string      `json:"headers,omitempty"`
+	Body         map[string]interface{} `json:"body,omitempty"`         // JSON body, or fields for form and multipart bodies
+	BodyType     string                 `json:"bodyType,omitempty"`     // json (default), raw, form, multipart, binary
+	RawBody      string                 `json:"rawBody,omitempty"`      // raw text body, or base64 data for binary bodies
+	BodyFile     string                 `json:"bodyFile,omitempty"`     // file sent as a binary body
+	Files        []HTTPFilePart         `json:"files,omitempty"`        // file parts of a multipart body
+	ResponseType string                 `json:"responseType,omitempty"` // auto (default, from Content-Type), json, xml, text, binary
+	Auth         *HTTPAuth              `json:"auth,omitempty"`
+}
+
+// TLSConfig represents the TLS client settings used to connect to a target.
+// Certificates and keys are PEM content, a path to a PEM file, or a {{NAME}} reference
+// to a (secret) environment variable holding either.
+type TLSConfig struct {
+	CACert             string `json:"caCert,omitempty"`             // CA bundle trusted in addition to the system roots
+	ClientCert         string `json:"clientCert,omitempty"`         // client certificate for mutual TLS
+	ClientKey          string `json:"clientKey,omitempty"`          // private key of the client certificate
+	ServerName         string `json:"serverName,omitempty"`         // SNI and verification host name override
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty"` // skip server certificate verification
+}
+
+// HTTPAuth represents the authentication applied to an HTTP request.
+// Credential fields may reference variables as {{NAME}}, resolved from the active
+// environment, values saved by setup hooks, and then the process environment.
+type HTTPAuth struct {
+	Type string `json:"type"` // basic, bearer, apikey, oauth2, hmac
+
+	Username string `json:"username,omitempty"` // basic, and the oauth2 password grant
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"` // bearer
+
+	Key  string `json:"key,omitempty"`  // apikey value, or the key id sent with hmac signatures
+	Name string `json:"name,omitempty"` // apikey header or query parameter name, default X-API-Key
+	In   string `json:"in,omitempty"`   // apikey location: header (default) or query
+
+	TokenURL     string `json:"tokenUrl,omitempty"`  // oauth2 token endpoint, relative to the base URL if it starts with /
+	GrantType    string `json:"grantType,omitempty"` // oauth2: client_credentials (default) or password
+	ClientID     string `json:"clientId,omitempty"`
+	ClientSecret string `json:"clientSecret,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+
+	Secret    string `json:"secret,omitempty"`    // hmac signing secret
+	Algorithm string `json:"algorithm,omitempty"` // hmac: sha256 (default), sha1, sha512
+	Header    string `json:"header,omitempty"`    // hmac signature header, default X-Signature
+}
+
+// HTTPFilePart represents a file part of a multipart/form-data body
+type HTTPFilePart struct {
+	Field         string `json:"field"`
+	Filename      string `json:"filename,omitempty"` // defaults to the base name of Path
+	Path          string `json:"path,omitempty"`     // file to upload
+	Content       string `json:"content,omitempty"`  // inline text content, used when Path is empty
+	ContentBase64 string `json:"contentBase64,omitempty"`
+	ContentType   string `json:"contentType,omitempty"`
 }
 
 // CommandTest represents a command line test configuration
 type CommandTest struct {
-	Cmd     string   `json:"cmd"`
-	Args    []string `json:"args,omitempty"`
-	Cwd     string   `json:"cwd,omitempty"`
-	Timeout int      `json:"timeout,omitempty"` // seconds
+	Cmd       string            `json:"cmd"`                 // program, or the script run by the shell in shell mode
+	Args      []string          `json:"args,omitempty"`      // shell mode: positional parameters $1, $2, ...
+	Cwd       string            `json:"cwd,omitempty"`       // relative paths are resolved against the workspace
+	Timeout   int               `json:"timeout,omitempty"`   // seconds
+	Env       map[string]string `json:"env,omitempty"`       // added to the process environment; values may reference {{NAME}}
+	Stdin     string            `json:"stdin,omitempty"`     // written to standard input; may reference {{NAME}}
+	Shell     bool              `json:"shell,omitempty"`     // run Cmd with sh -c (cmd /C on Windows)
+	Workspace *CommandWorkspace `json:"workspace,omitempty"` // temporary working directory, removed after the command exits
+}
+
+// CommandWorkspace represents a temporary working directory populated with fixture files.
+// Its path is also passed to the command as the WORKSPACE environment variable.
+type CommandWorkspace struct {
+	Files []WorkspaceFile `json:"files,omitempty"`
+}
+
+// WorkspaceFile represents a fixture file written into a command workspace
+type WorkspaceFile struct {
+	Path          string `json:"path"`                    // relative to the workspace
+	Content       string `json:"content,omitempty"`       // inline text content
+	ContentBase64 string `json:"contentBase64,omitempty"` // inline binary content
+	Source        string `json:"source,omitempty"`        // file copied into the workspace
+	Executable    bool   `json:"executable,omitempty"`    // mark the file executable, e.g. for scripts
+}
+
+// GRPCTest represents a gRPC test configuration
+type GRPCTest struct {
+	Address       string                 `json:"address,omitempty"` // host:port, defaults to the executor base URL host
+	Service       string                 `json:"service"`           // fully-qualified service name, e.g. helloworld.Greeter
+	Method        string                 `json:"method"`
+	Message       map[string]interface{} `json:"message,omitempty"`       // request message in protobuf JSON form
+	Metadata      map[string]string      `json:"metadata,omitempty"`      // outgoing metadata headers
+	DescriptorSet string                 `json:"descriptorSet,omitempty"` // base64 FileDescriptorSet; server reflection is used when empty
+	MaxMessages   int                    `json:"maxMessages,omitempty"`   // server-streaming: stop after N messages (0 = until EOF)
+	TLS           bool                   `json:"tls,omitempty"`
+	Timeout       int                    `json:"timeout,omitempty"` // seconds
+}
+
+// WebSocketTest represents a WebSocket test configuration
+type WebSocketTest struct {
+	URL          string            `json:"url"` // ws(s):// URL, or a path resolved against the base URL
+	Headers      map[string]string `json:"headers,omitempty"`
+	Subprotocols []string          `json:"subprotocols,omitempty"`
+	Steps        []WebSocketStep   `json:"steps,omitempty"`
+	Timeout      int               `json:"timeout,omitempty"` // seconds, whole script
+}
+
+// WebSocketStep represents one scripted action on a WebSocket connection
+type WebSocketStep struct {
+	Action     string      `json:"action"`               // send, expect, waitFor, sleep, close
+	Data       interface{} `json:"data,omitempty"`       // send: strings go out as text frames, other values as JSON
+	Binary     bool        `json:"binary,omitempty"`     // send: data is base64 and sent as a binary frame
+	Timeout    int         `json:"timeout,omitempty"`    // expect/waitFor/sleep: milliseconds
+	Assertions []Assertion `json:"assertions,omitempty"` // expect/waitFor: checks on the received message
+}
+
+// DatabaseTest represents a database query configuration (used by database tests and sql hooks)
+type DatabaseTest struct {
+	Driver     string        `json:"driver,omitempty"` // sqlite, postgres, mysql; inferred from the DSN when empty
+	Connection string        `json:"connection"`       // environment variable holding the DSN
+	Query      string        `json:"query"`
+	Params     []interface{} `json:"params,omitempty"`  // positional query parameters
+	Timeout    int           `json:"timeout,omitempty"` // seconds
+}
+
+// PerformanceTest represents load parameters for driving the test's HTTP request
+type PerformanceTest struct {
+	Concurrency int `json:"concurrency"`      // concurrent virtual users
+	RPS         int `json:"rps,omitempty"`    // global request rate limit (0 = unlimited)
+	Duration    int `json:"duration"`         // seconds
+	RampUp      int `json:"rampUp,omitempty"` // seconds to reach full concurrency
+}
+
+// SecurityTest represents a security scan of a target URL
+type SecurityTest struct {
+	URL             string            `json:"url,omitempty"`             // absolute URL, or a path resolved against the base URL
+	Checks          []string          `json:"checks,omitempty"`          // tls, headers, cors, auth; all when empty
+	Headers         map[string]string `json:"headers,omitempty"`         // sent with header and CORS probes, never with auth probes
+	TLS             *SecurityTLSCheck `json:"tls,omitempty"`             // TLS probe options
+	RequiredHeaders []string          `json:"requiredHeaders,omitempty"` // defaults to HSTS, CSP and X-Frame-Options
+	AllowedOrigins  []string          `json:"allowedOrigins,omitempty"`  // origins the target may legitimately allow
+	ProtectedPaths  []string          `json:"protectedPaths,omitempty"`  // "/admin" or "DELETE /users/1"; must reject anonymous requests
+	Ignore          []string          `json:"ignore,omitempty"`          // finding IDs to suppress, e.g. headers.csp
+	Timeout         int               `json:"timeout,omitempty"`         // seconds, per probe
+}
+
+// SecurityTLSCheck represents TLS probe options
+type SecurityTLSCheck struct {
+	MinVersion  string `json:"minVersion,omitempty"`  // lowest acceptable protocol version, default 1.2
+	MinCertDays int    `json:"minCertDays,omitempty"` // minimum remaining certificate validity, default 30
+	SkipVerify  bool   `json:"skipVerify,omitempty"`  // don't report untrusted certificates (self-signed targets)
 }
 
 // Assertion represents a test assertion
 type Assertion struct {
-	Type     string      `json:"type"`     // status_code, json_path, exit_code, stdout_contains, etc.
+	Type     string      `json:"type"` // status_code, header, cookie, response_time, json_path, json_schema, xpath, body, body_size, body_sha256, snapshot, expr, exit_code, stdout_contains, stderr_contains, grpc_status, row_count, threshold, etc.
+	Path     string      `json:"path,omitempty"`
+	Expected interface{} `json:"expected,omitempty"`
+	Operator string      `json:"operator,omitempty"` // equals, in, exists, not_exists, length, contains, all, etc.
+	Soft     bool        `json:"soft,omitempty"`     // report a failure without failing the test
+}
+
+// Extraction saves a value from an HTTP or command test's response as a variable,
+// referenced as {{name}} by the test's teardown hooks and the tests that follow it in a group run
+type Extraction struct {
+	Name  string `json:"name"`            // variable name
+	Type  string `json:"type,omitempty"`  // json_path (default), header, regex
+	Path  string `json:"path"`            // JSONPath into the body (command: stdout), header name, or regular expression
+	Group int    `json:"group,omitempty"` // regex capture group; defaults to the first group, or the whole match if there is none
+}
+
+// AssertionResult records the outcome of a single evaluated assertion
+type AssertionResult struct {
+	Type     string      `json:"type"`
 	Path     string      `json:"path,omitempty"`
+	Operator string      `json:"operator,omitempty"`
 	Expected interface{} `json:"expected,omitempty"`
-	Operator string      `json:"operator,omitempty"` // equals, in, exists, contains, etc.
+	Actual   interface{} `json:"actual,omitempty"`
+	Passed   bool        `json:"passed"`
+	Soft     bool        `json:"soft,omitempty"`
+	Message  string      `json:"message,omitempty"`
 }
 
 // Hook represents a lifecycle hook (setup or teardown)
 type Hook struct {
-	Type            string       `json:"type"`                      // http, command, sql
-	Name            string       `json:"name"`                      // descriptive name
-	HTTP            *HTTPTest    `json:"http,omitempty"`            // HTTP hook configuration
-	Command         *CommandTest `json:"command,omitempty"`         // Command hook configuration
-	SaveResponse    string       `json:"saveResponse,omitempty"`    // variable name to store response
-	RunOnFailure    bool         `json:"runOnFailure,omitempty"`    // for teardown: run even if test fails
-	ContinueOnError bool         `json:"continueOnError,omitempty"` // don't stop if hook fails
+	Type            string            `json:"type"`                      // http, command, sql
+	Name            string            `json:"name"`                      // descriptive name
+	HTTP            *HTTPTest         `json:"http,omitempty"`            // HTTP hook configuration
+	Command         *CommandTest      `json:"command,omitempty"`         // Command hook configuration
+	SQL             *DatabaseTest     `json:"sql,omitempty"`             // SQL hook configuration
+	SaveResponse    string            `json:"saveResponse,omitempty"`    // variable name to store response
+	Extract         map[string]string `json:"extract,omitempty"`         // variable name -> JSONPath into the hook response
+	RunOnFailure    bool              `json:"runOnFailure,omitempty"`    // for teardown: run even if test fails
+	ContinueOnError bool              `json:"continueOnError,omitempty"` // don't stop if hook fails
 }
 
 // TestResult represents the result of a test execution
 type TestResult struct {
-	TestID    string                 `json:"testId"`
-	Name      string                 `json:"name"`
-	Status    string                 `json:"status"` // passed, failed, error
-	StartTime time.Time              `json:"startTime"`
-	EndTime   time.Time              `json:"endTime"`
-	Duration  time.Duration          `json:"duration"`
-	Error     string                 `json:"error,omitempty"`
-	Failures  []string               `json:"failures,omitempty"`
-	Request   map[string]interface{} `json:"request,omitempty"`
-	Response  map[string]interface{} `json:"response,omitempty"`
+	TestID     string                 `json:"testId"`
+	Name       string                 `json:"name"`
+	Status     string                 `json:"status"` // passed, failed, error, timeout, cancelled
+	StartTime  time.Time              `json:"startTime"`
+	EndTime    time.Time              `json:"endTime"`
+	Duration   time.Duration          `json:"duration"`
+	Error      string                 `json:"error,omitempty"`
+	Failures   []string               `json:"failures,omitempty"`
+	Assertions []AssertionResult      `json:"assertions,omitempty"`
+	Request    map[string]interface{} `json:"request,omitempty"`
+	Response   map[string]interface{} `json:"response,omitempty"`
+	Metrics    map[string]interface{} `json:"metrics,omitempty"`
+	HAR        *HAR                   `json:"har,omitempty"`       // HTTP exchanges of hooks, the test and workflow steps
+	Variables  map[string]interface{} `json:"variables,omitempty"` // values saved by the test's extract block
 }
@@ -0,0 +1,265 @@
+package testcase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type runVariablesKey struct{}
+
+// RunVariables holds the variables extracted by the tests of a run, so that later tests
+// can reference values such as the ID of a resource created by an earlier one
+type RunVariables struct {
+	mu     sync.Mutex
+	values map[string]interface{}
+}
+
+// NewRunVariables creates an empty variable store for a run
+func NewRunVariables() *RunVariables {
+	return &RunVariables{values: make(map[string]interface{})}
+}
+
+// Set stores a variable
+func (v *RunVariables) Set(name string, value interface{}) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.values[name] = value
+}
+
+// Values returns a copy of the stored variables
+func (v *RunVariables) Values() map[string]interface{} {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	values := make(map[string]interface{}, len(v.values))
+	for name, value := range v.values {
+		values[name] = value
+	}
+	return values
+}
+
+// WithRunVariables returns a context whose tests start with the variables in vars
+// and add the values they extract to it
+func WithRunVariables(ctx context.Context, vars *RunVariables) context.Context {
+	return context.WithValue(ctx, runVariablesKey{}, vars)
+}
+
+// runVariablesFrom returns the run variables of ctx, or nil if tests are not chained
+func runVariablesFrom(ctx context.Context) *RunVariables {
+	vars, _ := ctx.Value(runVariablesKey{}).(*RunVariables)
+	return vars
+}
+
+// extractVariables saves the values selected by the test's extract block into hookCtx,
+// where teardown hooks can reference them, and into the run variables for later tests.
+// A value that cannot be extracted fails the test.
+func extractVariables(ctx context.Context, tc *TestCase, result *TestResult, hookCtx map[string]interface{}) {
+	if len(tc.Extract) == 0 || result.Response == nil {
+		return
+	}
+
+	result.Variables = make(map[string]interface{}, len(tc.Extract))
+	runVars := runVariablesFrom(ctx)
+	for _, extraction := range tc.Extract {
+		value, err := extractValue(extraction, result.Response)
+		if err != nil {
+			result.Failures = append(result.Failures, fmt.Sprintf("extract %s: %v", extraction.Name, err))
+			if result.Status == "passed" {
+				result.Status = "failed"
+			}
+			continue
+		}
+		hookCtx[extraction.Name] = value
+		result.Variables[extraction.Name] = value
+		if runVars != nil {
+			runVars.Set(extraction.Name, value)
+		}
+	}
+}
+
+// extractValue selects a value from an HTTP or command test response
+func extractValue(extraction Extraction, response map[string]interface{}) (interface{}, error) {
+	switch extraction.Type {
+	case "", "json_path":
+		body, ok := response["body"]
+		if stdout, isCommand := response["stdout"].(string); isCommand {
+			ok = json.Unmarshal([]byte(stdout), &body) == nil
+		}
+		if !ok {
+			return nil, fmt.Errorf("response has no JSON body")
+		}
+		value, found, err := LookupJSONPath(body, extraction.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, fmt.Errorf("JSON path %s not found", extraction.Path)
+		}
+		return value, nil
+
+	case "header":
+		headers, ok := response["headers"].(http.Header)
+		if !ok {
+			return nil, fmt.Errorf("response has no headers")
+		}
+		if _, present := headers[http.CanonicalHeaderKey(extraction.Path)]; !present {
+			return nil, fmt.Errorf("header %s not found", extraction.Path)
+		}
+		return headers.Get(extraction.Path), nil
+
+	case "regex":
+		re, err := regexp.Compile(extraction.Path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %v", err)
+		}
+		text, ok := response["bodyRaw"].(string)
+		if stdout, isCommand := response["stdout"].(string); isCommand {
+			text, ok = stdout, true
+		}
+		if !ok {
+			return nil, fmt.Errorf("response has no text body")
+		}
+		match := re.FindStringSubmatch(text)
+		if match == nil {
+			return nil, fmt.Errorf("regular expression %s did not match", extraction.Path)
+		}
+		group := extraction.Group
+		if group == 0 && len(match) > 1 {
+			group = 1
+		}
+		if group >= len(match) {
+			return nil, fmt.Errorf("regular expression %s has no group %d", extraction.Path, group)
+		}
+		return match[group], nil
+
+	default:
+		return nil, fmt.Errorf("unsupported extract type: %s", extraction.Type)
+	}
+}
+
+// templateHTTP returns a copy of an HTTP config with {{NAME}} references to vars replaced.
+// References to other names are left for the environment variable injector, which fills
+// them in on the copy, so the test case itself is never modified.
+func templateHTTP(config *HTTPTest, vars map[string]interface{}) *HTTPTest {
+	if config == nil {
+		return nil
+	}
+	templated := *config
+	templated.Path = templateString(config.Path, vars)
+	templated.RawBody = templateString(config.RawBody, vars)
+	if config.Headers != nil {
+		templated.Headers = make(map[string]string, len(config.Headers))
+		for name, value := range config.Headers {
+			templated.Headers[name] = templateString(value, vars)
+		}
+	}
+	if config.Body != nil {
+		templated.Body, _ = templateValue(config.Body, vars).(map[string]interface{})
+	}
+	return &templated
+}
+
+// templateCommand returns a copy of a command config with {{NAME}} references to vars replaced
+func templateCommand(config *CommandTest, vars map[string]interface{}) *CommandTest {
+	if config == nil {
+		return nil
+	}
+	templated := *config
+	templated.Cmd = templateString(config.Cmd, vars)
+	templated.Cwd = templateString(config.Cwd, vars)
+	templated.Stdin = templateString(config.Stdin, vars)
+	if config.Env != nil {
+		templated.Env = make(map[string]string, len(config.Env))
+		for name, value := range config.Env {
+			templated.Env[name] = templateString(value, vars)
+		}
+	}
+	if config.Args != nil {
+		templated.Args = make([]string, len(config.Args))
+		for i, arg := range config.Args {
+			templated.Args[i] = templateString(arg, vars)
+		}
+	}
+	return &templated
+}
+
+// templateQuery returns a copy of a database config with {{NAME}} references to vars replaced.
+// Parameters that are a single reference keep the variable's type.
+func templateQuery(config *DatabaseTest, vars map[string]interface{}) *DatabaseTest {
+	if config == nil || len(vars) == 0 {
+		return config
+	}
+	templated := *config
+	templated.Query = templateString(config.Query, vars)
+	if config.Params != nil {
+		templated.Params, _ = templateValue(config.Params, vars).([]interface{})
+	}
+	return &templated
+}
+
+// templateHook returns a copy of a hook with {{NAME}} references to vars replaced in its request
+func templateHook(hook *Hook, vars map[string]interface{}) *Hook {
+	if len(vars) == 0 {
+		return hook
+	}
+	templated := *hook
+	templated.HTTP = templateHTTP(hook.HTTP, vars)
+	templated.Command = templateCommand(hook.Command, vars)
+	templated.SQL = templateQuery(hook.SQL, vars)
+	return &templated
+}
+
+// templateValue replaces references in strings nested in maps and lists.
+// A string that is a single reference is replaced by the variable's value, keeping its type.
+func templateValue(value interface{}, vars map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if match := referencePattern.FindStringSubmatch(v); match != nil && match[0] == v {
+			if resolved, ok := vars[match[1]]; ok {
+				return resolved
+			}
+		}
+		return templateString(v, vars)
+	case map[string]interface{}:
+		templated := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			templated[key] = templateValue(item, vars)
+		}
+		return templated
+	case []interface{}:
+		templated := make([]interface{}, len(v))
+		for i, item := range v {
+			templated[i] = templateValue(item, vars)
+		}
+		return templated
+	default:
+		return value
+	}
+}
+
+// templateString replaces {{NAME}} references to vars in s
+func templateString(s string, vars map[string]interface{}) string {
+	if !strings.Contains(s, "{{") {
+		return s
+	}
+	return referencePattern.ReplaceAllStringFunc(s, func(placeholder string) string {
+		value, ok := vars[placeholder[2:len(placeholder)-2]]
+		if !ok {
+			return placeholder
+		}
+		switch v := value.(type) {
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		case map[string]interface{}, []interface{}:
+			data, _ := json.Marshal(v)
+			return string(data)
+		default:
+			return stringValue(v)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package testcase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderServer stores orders created with POST /orders and serves GET and DELETE /orders/{id}
+func orderServer() (*httptest.Server, *sync.Map) {
+	orders := &sync.Map{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && r.URL.Path == "/orders" {
+			var order map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&order)
+			order["id"] = 42
+			orders.Store("42", order)
+			w.Header().Set("Location", "/orders/42")
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(order)
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/orders/")
+		order, ok := orders.Load(id)
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case "DELETE":
+			orders.Delete(id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			json.NewEncoder(w).Encode(order)
+		}
+	}))
+	return server, orders
+}
+
+// TestExtract_ChainsGroupRun tests that values extracted by a test reach later tests of the run
+func TestExtract_ChainsGroupRun(t *testing.T) {
+	server, orders := orderServer()
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	ctx := WithRunVariables(context.Background(), NewRunVariables())
+
+	create := executor.ExecuteContext(ctx, &TestCase{
+		ID:   "create-order",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "POST", Path: "/orders", Body: map[string]interface{}{"sku": "A-1"}},
+		Extract: []Extraction{
+			{Name: "orderId", Path: "$.id"},
+			{Name: "orderUrl", Type: "header", Path: "Location"},
+			{Name: "orderSku", Type: "regex", Path: `"sku":"([^"]+)"`},
+		},
+		Assertions: []Assertion{{Type: "status_code", Expected: 201}},
+	})
+	require.Equal(t, "passed", create.Status, "failures: %v, error: %s", create.Failures, create.Error)
+	assert.Equal(t, map[string]interface{}{"orderId": float64(42), "orderUrl": "/orders/42", "orderSku": "A-1"}, create.Variables)
+
+	// References in the path and headers are replaced
+	get := executor.ExecuteContext(ctx, &TestCase{
+		ID:   "get-order",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "GET", Path: "{{orderUrl}}", Headers: map[string]string{"X-Order": "order-{{orderId}}"}},
+		Assertions: []Assertion{
+			{Type: "json_path", Path: "$.sku", Expected: "A-1"},
+			{Type: "expr", Expected: `vars.orderId == 42.0 && body.id == vars.orderId`},
+		},
+	})
+	assert.Equal(t, "passed", get.Status, "failures: %v, error: %s", get.Failures, get.Error)
+	assert.Equal(t, server.URL+"/orders/42", get.Request["url"])
+	assert.Equal(t, "order-42", get.Request["headers"].(map[string]string)["X-Order"])
+
+	del := executor.ExecuteContext(ctx, &TestCase{
+		ID:         "delete-order",
+		Type:       "http",
+		HTTP:       &HTTPTest{Method: "DELETE", Path: "/orders/{{orderId}}"},
+		Assertions: []Assertion{{Type: "status_code", Expected: 204}},
+	})
+	assert.Equal(t, "passed", del.Status, "failures: %v, error: %s", del.Failures, del.Error)
+	_, exists := orders.Load("42")
+	assert.False(t, exists)
+
+	// Without a shared run the variables are unknown and the reference is sent as is
+	get = executor.Execute(&TestCase{ID: "get-alone", Type: "http", HTTP: &HTTPTest{Method: "GET", Path: "/orders/{{orderId}}"}})
+	assert.Equal(t, server.URL+"/orders/{{orderId}}", get.Request["url"])
+}
+
+// TestExtract_TeardownAndFailures tests that extracted values reach the test's own teardown hooks
+// and that values which cannot be extracted fail the test
+func TestExtract_TeardownAndFailures(t *testing.T) {
+	server, orders := orderServer()
+	defer server.Close()
+
+	executor := NewExecutor(server.URL)
+	result := executor.Execute(&TestCase{
+		ID:      "create-and-clean-up",
+		Type:    "http",
+		HTTP:    &HTTPTest{Method: "POST", Path: "/orders", Body: map[string]interface{}{"sku": "B-2"}},
+		Extract: []Extraction{{Name: "orderId", Path: "$.id"}},
+		TeardownHooks: []Hook{
+			{Type: "http", Name: "delete", HTTP: &HTTPTest{Method: "DELETE", Path: "/orders/{{orderId}}"}},
+		},
+	})
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+	_, exists := orders.Load("42")
+	assert.False(t, exists, "teardown hook should delete the created order")
+
+	result = executor.Execute(&TestCase{
+		ID:   "missing-values",
+		Type: "http",
+		HTTP: &HTTPTest{Method: "POST", Path: "/orders", Body: map[string]interface{}{}},
+		Extract: []Extraction{
+			{Name: "missing", Path: "$.missing"},
+			{Name: "etag", Type: "header", Path: "ETag"},
+			{Name: "code", Type: "regex", Path: `code=(\d+)`},
+		},
+	})
+	assert.Equal(t, "failed", result.Status)
+	assert.Equal(t, []string{
+		"extract missing: JSON path $.missing not found",
+		"extract etag: header ETag not found",
+		`extract code: regular expression code=(\d+) did not match`,
+	}, result.Failures)
+
+	// Command tests extract from stdout
+	result = executor.Execute(&TestCase{
+		ID:      "command-extract",
+		Type:    "command",
+		Command: &CommandTest{Cmd: "echo", Args: []string{`{"version": "1.4.2"}`}},
+		Extract: []Extraction{
+			{Name: "version", Path: "$.version"},
+			{Name: "major", Type: "regex", Path: `"(\d+)\.\d+\.\d+"`},
+		},
+	})
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+	assert.Equal(t, map[string]interface{}{"version": "1.4.2", "major": "1"}, result.Variables)
+}
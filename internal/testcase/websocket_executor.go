@@ -0,0 +1,275 @@
+package testcase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// defaultWebSocketExpectTimeout is how long expect/waitFor steps wait when no timeout is given
+const defaultWebSocketExpectTimeout = 5 * time.Second
+
+// executeWebSocket executes a scripted WebSocket test
+func (e *UnifiedTestExecutor) executeWebSocket(ctx context.Context, tc *TestCase, result *TestResult) {
+	if tc.WebSocket == nil {
+		result.Status = "error"
+		result.Error = "WebSocket configuration missing"
+		return
+	}
+
+	wsURL, err := websocketURL(e.baseURL, tc.WebSocket.URL)
+	if err != nil {
+		result.Status = "error"
+		result.Error = fmt.Sprintf("invalid websocket url: %v", err)
+		return
+	}
+
+	header := http.Header{}
+	for k, v := range tc.WebSocket.Headers {
+		header.Set(k, v)
+	}
+
+	// Store request info
+	result.Request = map[string]interface{}{
+		"url":          wsURL,
+		"headers":      tc.WebSocket.Headers,
+		"subprotocols": tc.WebSocket.Subprotocols,
+		"steps":        tc.WebSocket.Steps,
+	}
+
+	tlsConfig, err := e.clientTLSConfig()
+	if err != nil {
+		result.Status = "error"
+		result.Error = err.Error()
+		return
+	}
+
+	dialer := websocket.Dialer{
+		HandshakeTimeout: 10 * time.Second,
+		Subprotocols:     tc.WebSocket.Subprotocols,
+		TLSClientConfig:  tlsConfig,
+	}
+	conn, resp, err := dialer.DialContext(ctx, wsURL, header)
+	if err != nil {
+		result.Status = "error"
+		if resp != nil {
+			result.Error = fmt.Sprintf("websocket handshake failed with status %d: %v", resp.StatusCode, err)
+		} else {
+			result.Error = fmt.Sprintf("websocket connection failed: %v", err)
+		}
+		return
+	}
+	defer conn.Close()
+
+	// Closing the connection unblocks pending reads when the test is interrupted
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	// Overall script deadline
+	timeout := 60 * time.Second
+	if tc.WebSocket.Timeout > 0 {
+		timeout = time.Duration(tc.WebSocket.Timeout) * time.Second
+	}
+	deadline := time.Now().Add(timeout)
+
+	var transcript []map[string]interface{}
+	defer func() {
+		result.Response = map[string]interface{}{
+			"statusCode":  resp.StatusCode,
+			"subprotocol": conn.Subprotocol(),
+			"messages":    transcript,
+		}
+	}()
+
+	for i, step := range tc.WebSocket.Steps {
+		if time.Now().After(deadline) {
+			result.Status = "failed"
+			result.Failures = append(result.Failures, fmt.Sprintf("websocket script timeout after %v", timeout))
+			return
+		}
+
+		switch step.Action {
+		case "send":
+			msgType, data, err := websocketPayload(step)
+			if err != nil {
+				result.Status = "error"
+				result.Error = fmt.Sprintf("step %d: %v", i+1, err)
+				return
+			}
+			conn.SetWriteDeadline(deadline)
+			if err := conn.WriteMessage(msgType, data); err != nil {
+				result.Status = "error"
+				result.Error = fmt.Sprintf("step %d: send failed: %v", i+1, err)
+				return
+			}
+			transcript = append(transcript, websocketTranscriptEntry("sent", msgType, data))
+
+		case "expect", "waitFor":
+			stepTimeout := defaultWebSocketExpectTimeout
+			if step.Timeout > 0 {
+				stepTimeout = time.Duration(step.Timeout) * time.Millisecond
+			}
+			stepDeadline := time.Now().Add(stepTimeout)
+			if stepDeadline.After(deadline) {
+				stepDeadline = deadline
+			}
+			conn.SetReadDeadline(stepDeadline)
+
+			// expect checks the next message; waitFor skips messages until one matches
+			var checked *TestResult
+			for {
+				msgType, data, err := conn.ReadMessage()
+				if err != nil {
+					result.Status = "failed"
+					if checked != nil {
+						result.Assertions = append(result.Assertions, checked.Assertions...)
+						result.Failures = append(result.Failures, checked.Failures...)
+					}
+					result.Failures = append(result.Failures,
+						fmt.Sprintf("step %d: no matching message received: %v", i+1, err))
+					return
+				}
+				entry := websocketTranscriptEntry("received", msgType, data)
+				transcript = append(transcript, entry)
+
+				checked = e.checkWebSocketMessage(step.Assertions, data, entry["json"])
+				if len(checked.Failures) == 0 {
+					result.Assertions = append(result.Assertions, checked.Assertions...)
+					break
+				}
+				if step.Action == "expect" {
+					result.Status = "failed"
+					result.Assertions = append(result.Assertions, checked.Assertions...)
+					for _, f := range checked.Failures {
+						result.Failures = append(result.Failures, fmt.Sprintf("step %d: %s", i+1, f))
+					}
+					return
+				}
+			}
+
+		case "sleep":
+			select {
+			case <-time.After(time.Duration(step.Timeout) * time.Millisecond):
+			case <-ctx.Done():
+				return
+			}
+
+		case "close":
+			conn.SetWriteDeadline(deadline)
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			return
+
+		default:
+			result.Status = "error"
+			result.Error = fmt.Sprintf("step %d: unsupported websocket action: %s", i+1, step.Action)
+			return
+		}
+	}
+}
+
+// checkWebSocketMessage evaluates message assertions into a scratch result holding the
+// assertion records and the failures of non-soft assertions
+func (e *UnifiedTestExecutor) checkWebSocketMessage(assertions []Assertion, data []byte, parsed interface{}) *TestResult {
+	checked := &TestResult{}
+	text := string(data)
+
+	for _, assertion := range assertions {
+		var actual interface{}
+		var failure string
+		switch assertion.Type {
+		case "json_path":
+			if parsed == nil {
+				failure = fmt.Sprintf("JSON path %s: message is not JSON", assertion.Path)
+			} else {
+				actual, failure = checkJSONPath(assertion, parsed)
+			}
+
+		case "message_contains":
+			actual = text
+			failure = checkOperator(assertion, "message", text, true, "contains")
+
+		case "message_equals":
+			actual = text
+			failure = checkOperator(assertion, "message", text, true, "equals")
+
+		default:
+			failure = fmt.Sprintf("unsupported websocket assertion type: %s", assertion.Type)
+		}
+
+		recordAssertion(checked, assertion, actual, failure)
+	}
+
+	return checked
+}
+
+// websocketPayload builds the frame type and bytes for a send step
+func websocketPayload(step WebSocketStep) (int, []byte, error) {
+	if step.Binary {
+		str, ok := step.Data.(string)
+		if !ok {
+			return 0, nil, fmt.Errorf("binary data must be a base64 string")
+		}
+		data, err := base64.StdEncoding.DecodeString(str)
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid base64 data: %v", err)
+		}
+		return websocket.BinaryMessage, data, nil
+	}
+
+	if str, ok := step.Data.(string); ok {
+		return websocket.TextMessage, []byte(str), nil
+	}
+
+	data, err := json.Marshal(step.Data)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to marshal data: %v", err)
+	}
+	return websocket.TextMessage, data, nil
+}
+
+// websocketTranscriptEntry records a frame for the test response
+func websocketTranscriptEntry(direction string, msgType int, data []byte) map[string]interface{} {
+	entry := map[string]interface{}{
+		"direction": direction,
+		"time":      time.Now(),
+	}
+	if msgType == websocket.BinaryMessage {
+		entry["type"] = "binary"
+		entry["data"] = base64.StdEncoding.EncodeToString(data)
+		return entry
+	}
+
+	entry["type"] = "text"
+	entry["data"] = string(data)
+	var parsed interface{}
+	if json.Unmarshal(data, &parsed) == nil {
+		entry["json"] = parsed
+	}
+	return entry
+}
+
+// websocketURL resolves the configured URL, mapping http(s) base URLs to ws(s)
+func websocketURL(baseURL, target string) (string, error) {
+	if strings.HasPrefix(target, "ws://") || strings.HasPrefix(target, "wss://") {
+		return target, nil
+	}
+
+	u, err := url.Parse(baseURL + target)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	case "http", "":
+		u.Scheme = "ws"
+	}
+	return u.String(), nil
+}
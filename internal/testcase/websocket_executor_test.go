@@ -0,0 +1,148 @@
+package testcase
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// startWebSocketServer starts an echo server that greets each client with a JSON welcome frame
+func startWebSocketServer(t *testing.T) *httptest.Server {
+	upgrader := websocket.Upgrader{Subprotocols: []string{"echo.v1"}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.WriteJSON(map[string]interface{}{"type": "welcome", "version": 2})
+		for {
+			msgType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			conn.WriteJSON(map[string]interface{}{"type": "ack"})
+			conn.WriteMessage(msgType, data)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// TestWebSocket_ScriptedExchange tests sending frames and asserting on received messages
+func TestWebSocket_ScriptedExchange(t *testing.T) {
+	server := startWebSocketServer(t)
+	executor := NewExecutor(server.URL)
+
+	result := executor.Execute(&TestCase{
+		ID:   "ws-echo",
+		Type: "websocket",
+		WebSocket: &WebSocketTest{
+			URL:          "/ws",
+			Headers:      map[string]string{"Authorization": "Bearer token"},
+			Subprotocols: []string{"echo.v1"},
+			Steps: []WebSocketStep{
+				{Action: "expect", Assertions: []Assertion{{Type: "json_path", Path: "$.type", Expected: "welcome"}}},
+				{Action: "send", Data: "hello"},
+				{Action: "waitFor", Assertions: []Assertion{{Type: "message_equals", Expected: "hello"}}},
+				{Action: "send", Data: map[string]interface{}{"op": "ping"}},
+				{Action: "waitFor", Assertions: []Assertion{{Type: "json_path", Path: "$.op", Expected: "ping"}}},
+				{Action: "close"},
+			},
+		},
+	})
+
+	assert.Equal(t, "passed", result.Status, "failures: %v, error: %s", result.Failures, result.Error)
+	assert.Equal(t, "echo.v1", result.Response["subprotocol"])
+	assert.Len(t, result.Response["messages"], 7)
+}
+
+// TestWebSocket_ExpectMismatch tests that expect fails on the first non-matching message
+func TestWebSocket_ExpectMismatch(t *testing.T) {
+	server := startWebSocketServer(t)
+	executor := NewExecutor(server.URL)
+
+	result := executor.Execute(&TestCase{
+		ID:   "ws-mismatch",
+		Type: "websocket",
+		WebSocket: &WebSocketTest{
+			URL:     "/ws",
+			Headers: map[string]string{"Authorization": "Bearer token"},
+			Steps: []WebSocketStep{
+				{Action: "expect", Assertions: []Assertion{{Type: "message_contains", Expected: "goodbye"}}},
+			},
+		},
+	})
+
+	assert.Equal(t, "failed", result.Status)
+	assert.Len(t, result.Failures, 1)
+}
+
+// TestWebSocket_WaitForTimeout tests that waitFor fails when no message matches in time
+func TestWebSocket_WaitForTimeout(t *testing.T) {
+	server := startWebSocketServer(t)
+	executor := NewExecutor(server.URL)
+
+	result := executor.Execute(&TestCase{
+		ID:   "ws-timeout",
+		Type: "websocket",
+		WebSocket: &WebSocketTest{
+			URL:     "/ws",
+			Headers: map[string]string{"Authorization": "Bearer token"},
+			Steps: []WebSocketStep{
+				{Action: "waitFor", Timeout: 200, Assertions: []Assertion{{Type: "json_path", Path: "$.type", Expected: "never"}}},
+			},
+		},
+	})
+
+	assert.Equal(t, "failed", result.Status)
+	assert.Contains(t, result.Failures[len(result.Failures)-1], "no matching message received")
+}
+
+// TestWebSocket_SleepInterrupted tests that a sleep step stops when the test times out
+func TestWebSocket_SleepInterrupted(t *testing.T) {
+	server := startWebSocketServer(t)
+	executor := NewExecutor(server.URL)
+
+	start := time.Now()
+	result := executor.Execute(&TestCase{
+		ID:      "ws-sleep",
+		Type:    "websocket",
+		Timeout: 1,
+		WebSocket: &WebSocketTest{
+			URL:     "/ws",
+			Headers: map[string]string{"Authorization": "Bearer token"},
+			Steps:   []WebSocketStep{{Action: "sleep", Timeout: 5000}},
+		},
+	})
+
+	assert.Less(t, time.Since(start), 3*time.Second)
+	assert.Equal(t, "timeout", result.Status)
+	assert.Equal(t, "test timed out after 1s", result.Error)
+}
+
+// TestWebSocket_HandshakeRejected tests the error reported when the upgrade is refused
+func TestWebSocket_HandshakeRejected(t *testing.T) {
+	server := startWebSocketServer(t)
+	executor := NewExecutor(server.URL)
+
+	result := executor.Execute(&TestCase{
+		ID:        "ws-unauthorized",
+		Type:      "websocket",
+		WebSocket: &WebSocketTest{URL: "/ws"},
+	})
+
+	assert.Equal(t, "error", result.Status)
+	assert.Contains(t, result.Error, "401")
+}
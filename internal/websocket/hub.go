@@ -24,7 +24,7 @@ type Hub struct {
 // Message represents a workflow event message
 type Message struct {
 	RunID   string      `json:"runId"`
-	Type    string      `json:"type"` // step_start, step_complete, step_log, variable_change
+	Type    string      `json:"type"` // step_start, step_complete, step_log, variable_change, run_cancelled
 	Payload interface{} `json:"payload"`
 }
 
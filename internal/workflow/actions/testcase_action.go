@@ -31,13 +31,14 @@ func (a *TestCaseAction) Execute(ctx *workflow.ActionContext) (*workflow.ActionR
 
 	// Step 3: Execute test case
 	ctx.Logger.Debug(ctx.StepID, fmt.Sprintf("Invoking UnifiedTestExecutor for test: %s", a.TestID))
-	result := ctx.UnifiedExecutor.Execute(testCaseWithInput)
+	result := ctx.UnifiedExecutor.ExecuteContext(ctx.Context, testCaseWithInput)
 
 	// Step 4: Convert result
 	if result.Status != "passed" {
 		return &workflow.ActionResult{
 			Status: "failed",
 			Error:  fmt.Errorf("test case failed: %s", result.Error),
+			HAR:    result.HAR,
 		}, nil
 	}
 
@@ -55,6 +56,7 @@ func (a *TestCaseAction) Execute(ctx *workflow.ActionContext) (*workflow.ActionR
 		Status:   "success",
 		Output:   output,
 		Duration: int(result.Duration.Milliseconds()),
+		HAR:      result.HAR,
 	}, nil
 }
 
@@ -0,0 +1,23 @@
+package workflow
+
+import (
+	"context"
+
+	"test-management-service/internal/testcase"
+)
+
+// TestCaseAdapter exposes a WorkflowExecutorImpl as the testcase.WorkflowExecutor that runs
+// workflow-type test cases. Executor is set after the unified executor it depends on is created.
+type TestCaseAdapter struct {
+	Executor *WorkflowExecutorImpl
+}
+
+// ExecuteContext runs a workflow by ID or definition and returns its result in the testcase form
+func (a *TestCaseAdapter) ExecuteContext(ctx context.Context, workflowID string, workflowDef interface{}) (*testcase.WorkflowResult, error) {
+	result, err := a.Executor.ExecuteContext(ctx, workflowID, workflowDef)
+	if err != nil {
+		return nil, err
+	}
+	converted := testcase.WorkflowResult(*result)
+	return &converted, nil
+}
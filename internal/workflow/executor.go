@@ -1,7 +1,9 @@
 package workflow
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -28,8 +30,20 @@ type WorkflowExecutorImpl struct {
 	unifiedExecutor  *testcase.UnifiedTestExecutor
 	hub              *websocket.Hub
 	variableInjector VariableInjector
+
+	mu     sync.Mutex
+	active map[string]*activeRun // runs in progress by run ID
+}
+
+// activeRun is a workflow run in progress that Cancel can stop
+type activeRun struct {
+	cancel context.CancelCauseFunc
+	done   chan struct{} // closed once the run's record is saved
 }
 
+// errRunCancelled is the cause recorded for runs stopped by Cancel
+var errRunCancelled = errors.New("workflow run cancelled")
+
 // NewWorkflowExecutor creates a new workflow executor
 func NewWorkflowExecutor(
 	db *gorm.DB,
@@ -47,6 +61,7 @@ func NewWorkflowExecutor(
 		unifiedExecutor:  unifiedExecutor,
 		hub:              hub,
 		variableInjector: variableInjector,
+		active:           make(map[string]*activeRun),
 	}
 
 	// Register built-in actions
@@ -62,6 +77,13 @@ func (e *WorkflowExecutorImpl) registerBuiltinActions() {
 
 // Execute runs a workflow
 func (e *WorkflowExecutorImpl) Execute(workflowID string, workflowDef interface{}) (*WorkflowResult, error) {
+	return e.ExecuteContext(context.Background(), workflowID, workflowDef)
+}
+
+// ExecuteContext runs a workflow under parent. Steps still running when parent is
+// cancelled or times out, or when the run is cancelled with Cancel, are interrupted,
+// and no further steps are started.
+func (e *WorkflowExecutorImpl) ExecuteContext(parent context.Context, workflowID string, workflowDef interface{}) (*WorkflowResult, error) {
 	// Step 1: Parse workflow definition
 	workflow, err := e.parseWorkflowDefinition(workflowID, workflowDef)
 	if err != nil {
@@ -85,8 +107,13 @@ func (e *WorkflowExecutorImpl) Execute(workflowID string, workflowDef interface{
 		return nil, fmt.Errorf("failed to create run record: %w", err)
 	}
 
+	// Let Cancel stop the run until its record is finalized
+	parent, cancel := context.WithCancelCause(parent)
+	defer e.untrack(runID, e.track(runID, cancel))
+
 	// Step 4: Initialize execution context
 	ctx := &ExecutionContext{
+		Context:     parent,
 		RunID:       runID,
 		Variables:   workflow.Variables,
 		StepOutputs: make(map[string]interface{}),
@@ -132,6 +159,9 @@ func (e *WorkflowExecutorImpl) Execute(workflowID string, workflowDef interface{
 	// Step 6: Execute steps layer by layer
 	var execError error
 	for _, layer := range layers {
+		if parent.Err() != nil {
+			break
+		}
 		if err := e.executeLayer(ctx, layer, workflow.Steps); err != nil {
 			execError = err
 			break
@@ -142,7 +172,13 @@ func (e *WorkflowExecutorImpl) Execute(workflowID string, workflowDef interface{
 	run.EndTime = time.Now()
 	run.Duration = int(run.EndTime.Sub(run.StartTime).Milliseconds())
 
-	if execError != nil {
+	if err := parent.Err(); err != nil {
+		run.Status = "cancelled"
+		if errors.Is(err, context.DeadlineExceeded) {
+			run.Status = "timeout"
+		}
+		run.Error = context.Cause(parent).Error()
+	} else if execError != nil {
 		run.Status = "failed"
 		run.Error = execError.Error()
 	} else {
@@ -153,10 +189,50 @@ func (e *WorkflowExecutorImpl) Execute(workflowID string, workflowDef interface{
 	run.Context = models.JSONB{"variables": ctx.Variables, "outputs": ctx.StepOutputs}
 	e.db.Save(run)
 
+	if run.Status == "cancelled" && e.hub != nil {
+		e.hub.Broadcast(runID, "run_cancelled", map[string]interface{}{
+			"status":   run.Status,
+			"error":    run.Error,
+			"duration": run.Duration,
+		})
+	}
+
 	// Step 8: Build result
 	return e.buildWorkflowResult(ctx, run), nil
 }
 
+// Cancel stops a workflow run in progress: no further steps are started and running steps are
+// interrupted. It returns a channel that is closed once the run's record is saved, or false if
+// the run is not in progress.
+func (e *WorkflowExecutorImpl) Cancel(runID string) (<-chan struct{}, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	run, ok := e.active[runID]
+	if !ok {
+		return nil, false
+	}
+	run.cancel(errRunCancelled)
+	return run.done, true
+}
+
+// track registers a run in progress and returns the active run to pass to untrack
+func (e *WorkflowExecutorImpl) track(runID string, cancel context.CancelCauseFunc) *activeRun {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	run := &activeRun{cancel: cancel, done: make(chan struct{})}
+	e.active[runID] = run
+	return run
+}
+
+// untrack removes a finished run and releases anyone waiting for it
+func (e *WorkflowExecutorImpl) untrack(runID string, run *activeRun) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.active, runID)
+	run.cancel(nil)
+	close(run.done)
+}
+
 // parseWorkflowDefinition parses workflow from various formats
 func (e *WorkflowExecutorImpl) parseWorkflowDefinition(workflowID string, workflowDef interface{}) (*WorkflowDefinition, error) {
 	var workflow WorkflowDefinition
@@ -367,10 +443,12 @@ func (e *WorkflowExecutorImpl) executeStep(ctx *ExecutionContext, step *Workflow
 	}
 
 	// Build action context
+	variables, stepOutputs := ctx.snapshot()
 	actionCtx := &ActionContext{
+		Context:         ctx.Context,
 		StepID:          step.ID,
-		Variables:       ctx.Variables,
-		StepOutputs:     ctx.StepOutputs,
+		Variables:       variables,
+		StepOutputs:     stepOutputs,
 		TestCaseRepo:    e.testCaseRepo,
 		UnifiedExecutor: e.unifiedExecutor,
 		Logger:          ctx.Logger,
@@ -389,9 +467,15 @@ func (e *WorkflowExecutorImpl) executeStep(ctx *ExecutionContext, step *Workflow
 			break
 		}
 		if attempt < maxAttempts {
+			if ctx.Context.Err() != nil {
+				break
+			}
 			ctx.Logger.Warn(step.ID, fmt.Sprintf("Attempt %d failed, retrying...", attempt))
 			if step.Retry != nil && step.Retry.Interval > 0 {
-				time.Sleep(time.Duration(step.Retry.Interval) * time.Millisecond)
+				select {
+				case <-time.After(time.Duration(step.Retry.Interval) * time.Millisecond):
+				case <-ctx.Context.Done():
+				}
 			}
 		}
 	}
@@ -407,14 +491,17 @@ func (e *WorkflowExecutorImpl) executeStep(ctx *ExecutionContext, step *Workflow
 		} else if result.Error != nil {
 			stepExec.Error = result.Error.Error()
 		}
+		stepExec.OutputData = stepOutputData(result)
 		e.db.Save(stepExec)
 
 		// Store step result
+		ctx.mu.Lock()
 		ctx.StepResults[step.ID] = &StepExecutionResult{
 			Status:   "failed",
 			Duration: stepExec.Duration,
 			Error:    stepExec.Error,
 		}
+		ctx.mu.Unlock()
 
 		// Handle error strategy
 		if step.OnError == "continue" {
@@ -426,31 +513,43 @@ func (e *WorkflowExecutorImpl) executeStep(ctx *ExecutionContext, step *Workflow
 
 	// Success - save output
 	stepExec.Status = "success"
+	stepExec.OutputData = stepOutputData(result)
 	if result != nil && result.Output != nil {
-		stepExec.OutputData = models.JSONB(result.Output)
-
 		// Save to step outputs
+		ctx.mu.Lock()
 		ctx.StepOutputs[step.ID] = result.Output
 
 		// Map output variables
 		if step.Output != nil {
 			for varName, outputPath := range step.Output {
-				if value, exists := result.Output[outputPath]; exists {
+				// Plain output keys map directly; anything else is a JSONPath into the output
+				value, exists := result.Output[outputPath]
+				if !exists {
+					var err error
+					value, exists, err = testcase.LookupJSONPath(result.Output, outputPath)
+					if err != nil {
+						ctx.Logger.Warn(step.ID, fmt.Sprintf("Output mapping %s: %v", varName, err))
+					}
+				}
+				if exists {
 					oldValue := ctx.Variables[varName]
 					ctx.Variables[varName] = value
 					ctx.VarTracker.Track(step.ID, varName, oldValue, value, "update")
 				}
 			}
 		}
+		ctx.mu.Unlock()
 	}
 	e.db.Save(stepExec)
 
 	// Store step result
+	ctx.mu.Lock()
 	ctx.StepResults[step.ID] = &StepExecutionResult{
 		Status:   "success",
 		Duration: stepExec.Duration,
 		Output:   result.Output,
 	}
+	ctx.mu.Unlock()
 
 	// Broadcast step complete event
 	if e.hub != nil {
@@ -488,6 +587,22 @@ func (e *WorkflowExecutorImpl) getActionForStep(step *WorkflowStep) (Action, err
 	}
 }
 
+// stepOutputData returns the snapshot saved with a step's execution record: the step's output
+// and, when the step made HTTP requests, their HAR
+func stepOutputData(result *ActionResult) models.JSONB {
+	if result == nil || (result.Output == nil && result.HAR == nil) {
+		return nil
+	}
+	data := make(models.JSONB, len(result.Output)+1)
+	for key, value := range result.Output {
+		data[key] = value
+	}
+	if result.HAR != nil {
+		data["har"] = result.HAR
+	}
+	return data
+}
+
 // evaluateCondition evaluates a simple condition expression
 func (e *WorkflowExecutorImpl) evaluateCondition(expr string, ctx *ExecutionContext) bool {
 	// Simple implementation - just check if variable exists and is truthy
@@ -577,15 +692,45 @@ func (a *TestCaseActionWrapper) Execute(ctx *ActionContext) (*ActionResult, erro
 		data, _ := json.Marshal(tc.CommandConfig)
 		json.Unmarshal(data, &cmdConfig)
 		testCase.Command = &cmdConfig
+	case "grpc":
+		var grpcConfig testcase.GRPCTest
+		data, _ := json.Marshal(tc.GRPCConfig)
+		json.Unmarshal(data, &grpcConfig)
+		testCase.GRPC = &grpcConfig
+	case "websocket":
+		var wsConfig testcase.WebSocketTest
+		data, _ := json.Marshal(tc.WebSocketConfig)
+		json.Unmarshal(data, &wsConfig)
+		testCase.WebSocket = &wsConfig
+	case "database":
+		var dbConfig testcase.DatabaseTest
+		data, _ := json.Marshal(tc.DatabaseConfig)
+		json.Unmarshal(data, &dbConfig)
+		testCase.Database = &dbConfig
+	case "performance":
+		var httpConfig testcase.HTTPTest
+		data, _ := json.Marshal(tc.HTTPConfig)
+		json.Unmarshal(data, &httpConfig)
+		testCase.HTTP = &httpConfig
+		var perfConfig testcase.PerformanceTest
+		data, _ = json.Marshal(tc.PerformanceConfig)
+		json.Unmarshal(data, &perfConfig)
+		testCase.Performance = &perfConfig
+	case "security":
+		var secConfig testcase.SecurityTest
+		data, _ := json.Marshal(tc.SecurityConfig)
+		json.Unmarshal(data, &secConfig)
+		testCase.Security = &secConfig
 	}
 
 	// Execute
-	result := ctx.UnifiedExecutor.Execute(testCase)
+	result := ctx.UnifiedExecutor.ExecuteContext(ctx.Context, testCase)
 
 	if result.Status != "passed" {
 		return &ActionResult{
 			Status: "failed",
 			Error:  fmt.Errorf("test failed: %s", result.Error),
+			HAR:    result.HAR,
 		}, nil
 	}
 
@@ -597,6 +742,7 @@ func (a *TestCaseActionWrapper) Execute(ctx *ActionContext) (*ActionResult, erro
 			"duration": result.Duration.Milliseconds(),
 		},
 		Duration: int(result.Duration.Milliseconds()),
+		HAR:      result.HAR,
 	}, nil
 }
 
@@ -625,12 +771,13 @@ func (a *HTTPActionWrapper) Execute(ctx *ActionContext) (*ActionResult, error) {
 	json.Unmarshal(data, &httpConfig)
 	testCase.HTTP = &httpConfig
 
-	result := ctx.UnifiedExecutor.Execute(testCase)
+	result := ctx.UnifiedExecutor.ExecuteContext(ctx.Context, testCase)
 
 	if result.Status != "passed" {
 		return &ActionResult{
 			Status: "failed",
 			Error:  fmt.Errorf("HTTP request failed: %s", result.Error),
+			HAR:    result.HAR,
 		}, nil
 	}
 
@@ -641,6 +788,7 @@ func (a *HTTPActionWrapper) Execute(ctx *ActionContext) (*ActionResult, error) {
 			"response": result.Response,
 		},
 		Duration: int(result.Duration.Milliseconds()),
+		HAR:      result.HAR,
 	}, nil
 }
 
@@ -665,7 +813,7 @@ func (a *CommandActionWrapper) Execute(ctx *ActionContext) (*ActionResult, error
 	json.Unmarshal(data, &cmdConfig)
 	testCase.Command = &cmdConfig
 
-	result := ctx.UnifiedExecutor.Execute(testCase)
+	result := ctx.UnifiedExecutor.ExecuteContext(ctx.Context, testCase)
 
 	if result.Status != "passed" {
 		return &ActionResult{
@@ -1,6 +1,9 @@
 package workflow
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -51,7 +54,7 @@ func TestWorkflowExecutor_SimpleWorkflow(t *testing.T) {
 	go hub.Run()
 
 	// Create workflow executor
-	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, hub)
+	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, hub, nil)
 
 	// Define a simple workflow with command steps
 	workflowDef := map[string]interface{}{
@@ -97,7 +100,7 @@ func TestWorkflowExecutor_ParallelSteps(t *testing.T) {
 	testCaseRepo := repository.NewWorkflowTestCaseRepository(db)
 	workflowRepo := repository.NewWorkflowRepository(db)
 	unifiedExecutor := testcase.NewExecutor("http://localhost:8080")
-	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil)
+	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil, nil)
 
 	// Define workflow with parallel steps (no dependencies)
 	workflowDef := map[string]interface{}{
@@ -147,7 +150,7 @@ func TestWorkflowExecutor_SequentialSteps(t *testing.T) {
 	testCaseRepo := repository.NewWorkflowTestCaseRepository(db)
 	workflowRepo := repository.NewWorkflowRepository(db)
 	unifiedExecutor := testcase.NewExecutor("http://localhost:8080")
-	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil)
+	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil, nil)
 
 	// Define workflow with sequential steps
 	workflowDef := map[string]interface{}{
@@ -199,7 +202,7 @@ func TestWorkflowExecutor_CycleDetection(t *testing.T) {
 	testCaseRepo := repository.NewWorkflowTestCaseRepository(db)
 	workflowRepo := repository.NewWorkflowRepository(db)
 	unifiedExecutor := testcase.NewExecutor("http://localhost:8080")
-	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil)
+	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil, nil)
 
 	// Define workflow with circular dependency
 	workflowDef := map[string]interface{}{
@@ -249,7 +252,7 @@ func TestWorkflowExecutor_StepFailure(t *testing.T) {
 	testCaseRepo := repository.NewWorkflowTestCaseRepository(db)
 	workflowRepo := repository.NewWorkflowRepository(db)
 	unifiedExecutor := testcase.NewExecutor("http://localhost:8080")
-	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil)
+	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil, nil)
 
 	// Define workflow with command that doesn't exist
 	workflowDef := map[string]interface{}{
@@ -281,7 +284,7 @@ func TestWorkflowExecutor_ContinueOnError(t *testing.T) {
 	testCaseRepo := repository.NewWorkflowTestCaseRepository(db)
 	workflowRepo := repository.NewWorkflowRepository(db)
 	unifiedExecutor := testcase.NewExecutor("http://localhost:8080")
-	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil)
+	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil, nil)
 
 	// Define workflow with failing step that continues
 	workflowDef := map[string]interface{}{
@@ -324,7 +327,7 @@ func TestWorkflowExecutor_RetryLogic(t *testing.T) {
 	testCaseRepo := repository.NewWorkflowTestCaseRepository(db)
 	workflowRepo := repository.NewWorkflowRepository(db)
 	unifiedExecutor := testcase.NewExecutor("http://localhost:8080")
-	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil)
+	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil, nil)
 
 	// Define workflow with retry
 	workflowDef := map[string]interface{}{
@@ -377,7 +380,7 @@ func TestWorkflowExecutor_TestCaseAction(t *testing.T) {
 	testCaseRepo := repository.NewWorkflowTestCaseRepository(db)
 	workflowRepo := repository.NewWorkflowRepository(db)
 	unifiedExecutor := testcase.NewExecutor("http://localhost:8080")
-	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil)
+	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil, nil)
 
 	// Define workflow that references test case
 	workflowDef := map[string]interface{}{
@@ -408,7 +411,7 @@ func TestWorkflowExecutor_StepLogging(t *testing.T) {
 	testCaseRepo := repository.NewWorkflowTestCaseRepository(db)
 	workflowRepo := repository.NewWorkflowRepository(db)
 	unifiedExecutor := testcase.NewExecutor("http://localhost:8080")
-	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil)
+	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil, nil)
 
 	workflowDef := map[string]interface{}{
 		"name": "logging-test",
@@ -442,7 +445,7 @@ func TestWorkflowExecutor_StepExecutionRecords(t *testing.T) {
 	testCaseRepo := repository.NewWorkflowTestCaseRepository(db)
 	workflowRepo := repository.NewWorkflowRepository(db)
 	unifiedExecutor := testcase.NewExecutor("http://localhost:8080")
-	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil)
+	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil, nil)
 
 	workflowDef := map[string]interface{}{
 		"name": "execution-tracking-test",
@@ -470,3 +473,231 @@ func TestWorkflowExecutor_StepExecutionRecords(t *testing.T) {
 	assert.Equal(t, "step1", executions[0].StepID)
 	assert.Equal(t, "success", executions[0].Status)
 }
+
+// TestWorkflowExecutor_OutputMappingJSONPath tests mapping step outputs to variables with JSONPath
+func TestWorkflowExecutor_OutputMappingJSONPath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":"a","status":"closed"},{"id":"b","status":"active"},{"id":"c","status":"active"}]`))
+	}))
+	defer server.Close()
+
+	db := setupTestDB(t)
+
+	testCaseRepo := repository.NewWorkflowTestCaseRepository(db)
+	workflowRepo := repository.NewWorkflowRepository(db)
+	unifiedExecutor := testcase.NewExecutor(server.URL)
+	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil, nil)
+
+	workflowDef := map[string]interface{}{
+		"name": "output-mapping-test",
+		"steps": map[string]interface{}{
+			"step1": map[string]interface{}{
+				"id":   "step1",
+				"name": "List Orders",
+				"type": "http",
+				"config": map[string]interface{}{
+					"method": "GET",
+					"path":   "/orders",
+				},
+				"output": map[string]interface{}{
+					"stepStatus": "status",
+					"firstId":    "$.response.body[0].id",
+					"activeIds":  "$.response.body[?(@.status=='active')].id",
+				},
+			},
+		},
+	}
+
+	result, err := executor.Execute("output-mapping-workflow", workflowDef)
+	require.NoError(t, err)
+
+	assert.Equal(t, "success", result.Status)
+	assert.Equal(t, "passed", result.Context["stepStatus"])
+	assert.Equal(t, "a", result.Context["firstId"])
+	assert.Equal(t, []interface{}{"b", "c"}, result.Context["activeIds"])
+}
+
+// TestWorkflowExecutor_StepHAR tests that the HTTP exchanges of passing and failing steps are
+// saved with their execution records
+func TestWorkflowExecutor_StepHAR(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	db := setupTestDB(t)
+
+	testCaseRepo := repository.NewWorkflowTestCaseRepository(db)
+	workflowRepo := repository.NewWorkflowRepository(db)
+	unifiedExecutor := testcase.NewExecutor(server.URL)
+	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil, nil)
+
+	workflowDef := map[string]interface{}{
+		"name": "har-test",
+		"steps": map[string]interface{}{
+			"ok": map[string]interface{}{
+				"id":     "ok",
+				"name":   "Health",
+				"type":   "http",
+				"config": map[string]interface{}{"method": "GET", "path": "/health"},
+			},
+			"fail": map[string]interface{}{
+				"id":   "fail",
+				"name": "Token Unavailable",
+				"type": "http",
+				"config": map[string]interface{}{
+					"method": "GET",
+					"path":   "/orders",
+					"auth":   map[string]interface{}{"type": "oauth2", "tokenUrl": "/fail", "clientId": "cli", "clientSecret": "secret"},
+				},
+				"onError": "continue",
+			},
+		},
+	}
+
+	result, err := executor.Execute("har-workflow", workflowDef)
+	require.NoError(t, err)
+
+	var executions []models.WorkflowStepExecution
+	require.NoError(t, db.Where("run_id = ?", result.RunID).Order("step_id").Find(&executions).Error)
+	require.Len(t, executions, 2)
+
+	paths := map[string]string{"ok": "/health", "fail": "/fail"}
+	statuses := map[string]string{"ok": "success", "fail": "failed"}
+	for _, exec := range executions {
+		assert.Equal(t, statuses[exec.StepID], exec.Status)
+		har, ok := exec.OutputData["har"].(map[string]interface{})
+		require.True(t, ok, "step %s has no HAR: %v", exec.StepID, exec.OutputData)
+		entries := har["log"].(map[string]interface{})["entries"].([]interface{})
+		require.Len(t, entries, 1)
+		request := entries[0].(map[string]interface{})["request"].(map[string]interface{})
+		assert.Equal(t, server.URL+paths[exec.StepID], request["url"])
+	}
+
+	// The HAR is not part of the outputs that later steps and variables refer to
+	assert.NotContains(t, result.Context, "har")
+	var run models.WorkflowRun
+	require.NoError(t, db.Where("run_id = ?", result.RunID).First(&run).Error)
+	assert.NotContains(t, run.Context["outputs"].(map[string]interface{})["ok"], "har")
+}
+
+// TestWorkflowExecutor_Cancellation tests that cancelling a run stops the running step and skips later layers
+func TestWorkflowExecutor_Cancellation(t *testing.T) {
+	db := setupTestDB(t)
+
+	testCaseRepo := repository.NewWorkflowTestCaseRepository(db)
+	workflowRepo := repository.NewWorkflowRepository(db)
+	unifiedExecutor := testcase.NewExecutor("http://localhost:8080")
+	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil, nil)
+
+	workflowDef := map[string]interface{}{
+		"name": "cancel-test",
+		"steps": map[string]interface{}{
+			"slow": map[string]interface{}{
+				"id":   "slow",
+				"name": "Slow Step",
+				"type": "command",
+				"config": map[string]interface{}{
+					"cmd":  "sleep",
+					"args": []string{"5"},
+				},
+			},
+			"after": map[string]interface{}{
+				"id":        "after",
+				"name":      "After Step",
+				"type":      "command",
+				"dependsOn": []string{"slow"},
+				"config": map[string]interface{}{
+					"cmd":  "echo",
+					"args": []string{"done"},
+				},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	result, err := executor.ExecuteContext(ctx, "cancel-workflow", workflowDef)
+	require.NoError(t, err)
+
+	assert.Less(t, time.Since(start), 3*time.Second)
+	assert.Equal(t, "timeout", result.Status)
+	assert.Equal(t, 0, result.CompletedSteps)
+
+	var steps []models.WorkflowStepExecution
+	db.Where("run_id = ?", result.RunID).Find(&steps)
+	require.Len(t, steps, 1)
+	assert.Equal(t, "slow", steps[0].StepID)
+}
+
+func TestWorkflowExecutor_Cancel(t *testing.T) {
+	db := setupTestDB(t)
+
+	testCaseRepo := repository.NewWorkflowTestCaseRepository(db)
+	workflowRepo := repository.NewWorkflowRepository(db)
+	unifiedExecutor := testcase.NewExecutor("http://localhost:8080")
+	executor := NewWorkflowExecutor(db, testCaseRepo, workflowRepo, unifiedExecutor, nil, nil)
+
+	workflowDef := map[string]interface{}{
+		"name": "cancel-api-test",
+		"steps": map[string]interface{}{
+			"slow": map[string]interface{}{
+				"id":   "slow",
+				"name": "Slow Step",
+				"type": "command",
+				"config": map[string]interface{}{
+					"cmd":  "sleep",
+					"args": []string{"5"},
+				},
+			},
+			"after": map[string]interface{}{
+				"id":        "after",
+				"name":      "After Step",
+				"type":      "command",
+				"dependsOn": []string{"slow"},
+				"config": map[string]interface{}{
+					"cmd":  "echo",
+					"args": []string{"done"},
+				},
+			},
+		},
+	}
+
+	results := make(chan *WorkflowResult, 1)
+	go func() {
+		result, err := executor.Execute("cancel-api-workflow", workflowDef)
+		assert.NoError(t, err)
+		results <- result
+	}()
+
+	// Wait for the run to start, then cancel it
+	var run models.WorkflowRun
+	require.Eventually(t, func() bool {
+		return db.Where("workflow_id = ? AND status = ?", "cancel-api-workflow", "running").First(&run).Error == nil
+	}, 2*time.Second, 10*time.Millisecond)
+
+	start := time.Now()
+	done, ok := executor.Cancel(run.RunID)
+	require.True(t, ok)
+	<-done
+	assert.Less(t, time.Since(start), 3*time.Second)
+
+	result := <-results
+	assert.Equal(t, "cancelled", result.Status)
+	assert.Equal(t, 0, result.CompletedSteps)
+
+	require.NoError(t, db.Where("run_id = ?", run.RunID).First(&run).Error)
+	assert.Equal(t, "cancelled", run.Status)
+	assert.Equal(t, "workflow run cancelled", run.Error)
+
+	// Finished runs cannot be cancelled
+	_, ok = executor.Cancel(run.RunID)
+	assert.False(t, ok)
+}
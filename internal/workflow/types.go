@@ -1,6 +1,8 @@
 package workflow
 
 import (
+	"context"
+	"sync"
 	"time"
 
 	"test-management-service/internal/models"
@@ -15,7 +17,7 @@ type WorkflowExecutor interface {
 // WorkflowResult represents workflow execution result
 type WorkflowResult struct {
 	RunID          string
-	Status         string // success, failed, cancelled
+	Status         string // success, failed, timeout, cancelled
 	StartTime      time.Time
 	EndTime        time.Time
 	Duration       int
@@ -35,6 +37,7 @@ type Action interface {
 
 // ActionContext contains execution context for actions
 type ActionContext struct {
+	Context         context.Context // Cancelled when the run times out or is cancelled
 	StepID          string
 	Variables       map[string]interface{} // Global variables
 	StepOutputs     map[string]interface{} // Step outputs
@@ -49,6 +52,7 @@ type ActionResult struct {
 	Output   map[string]interface{}
 	Duration int
 	Error    error
+	HAR      *testcase.HAR // HTTP exchanges of the step, saved with its execution record
 }
 
 // StepLogger for step-level logging
@@ -71,12 +75,32 @@ type WorkflowRepository interface {
 
 // ExecutionContext tracks workflow execution state
 type ExecutionContext struct {
+	Context     context.Context
 	RunID       string
 	Variables   map[string]interface{}
 	StepOutputs map[string]interface{}
 	StepResults map[string]*StepExecutionResult
 	Logger      StepLogger
 	VarTracker  VariableChangeTracker
+
+	mu sync.Mutex // Guards the maps while the steps of a layer run in parallel
+}
+
+// snapshot returns copies of the variables and step outputs for a step to read while
+// other steps update them
+func (c *ExecutionContext) snapshot() (map[string]interface{}, map[string]interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	variables := make(map[string]interface{}, len(c.Variables))
+	for key, value := range c.Variables {
+		variables[key] = value
+	}
+	outputs := make(map[string]interface{}, len(c.StepOutputs))
+	for key, value := range c.StepOutputs {
+		outputs[key] = value
+	}
+	return variables, outputs
 }
 
 // StepExecutionResult tracks individual step results
@@ -2,6 +2,7 @@ package integration
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -639,9 +640,9 @@ type WorkflowExecutorAdapter struct {
 	impl *workflow.WorkflowExecutorImpl
 }
 
-// Execute adapts the workflow executor to return testcase.WorkflowResult
-func (a *WorkflowExecutorAdapter) Execute(workflowID string, workflowDef interface{}) (*testcase.WorkflowResult, error) {
-	result, err := a.impl.Execute(workflowID, workflowDef)
+// ExecuteContext adapts the workflow executor to return testcase.WorkflowResult
+func (a *WorkflowExecutorAdapter) ExecuteContext(ctx context.Context, workflowID string, workflowDef interface{}) (*testcase.WorkflowResult, error) {
+	result, err := a.impl.ExecuteContext(ctx, workflowID, workflowDef)
 	if err != nil {
 		return nil, err
 	}
@@ -757,6 +758,8 @@ func setupTestEnvironment(t *testing.T) (*gorm.DB, *gin.Engine) {
 		testResultRepo,
 		testRunRepo,
 		unifiedExecutor,
+		nil, // No WebSocket hub for tests
+		1,
 	)
 
 	// Create workflow service
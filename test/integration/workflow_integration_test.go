@@ -2,6 +2,7 @@ package integration
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -28,9 +29,9 @@ type WorkflowExecutorAdapter struct {
 	impl *workflow.WorkflowExecutorImpl
 }
 
-// Execute adapts the workflow executor to return testcase.WorkflowResult
-func (a *WorkflowExecutorAdapter) Execute(workflowID string, workflowDef interface{}) (*testcase.WorkflowResult, error) {
-	result, err := a.impl.Execute(workflowID, workflowDef)
+// ExecuteContext adapts the workflow executor to return testcase.WorkflowResult
+func (a *WorkflowExecutorAdapter) ExecuteContext(ctx context.Context, workflowID string, workflowDef interface{}) (*testcase.WorkflowResult, error) {
+	result, err := a.impl.ExecuteContext(ctx, workflowID, workflowDef)
 	if err != nil {
 		return nil, err
 	}
@@ -139,6 +140,8 @@ func setupTestEnvironment(t *testing.T) (*gin.Engine, *gorm.DB, *websocket.Hub)
 		testResultRepo,
 		testRunRepo,
 		unifiedExecutor,
+		hub,
+		1,
 	)
 
 	workflowService := service.NewWorkflowService(